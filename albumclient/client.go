@@ -0,0 +1,151 @@
+package albumclient
+
+import (
+	"awsomeProject/auth"
+	"awsomeProject/pb"
+	"awsomeProject/tracing"
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// コンテキストに期限が設定されていない呼び出しに設定する期限のデフォルト値
+const DefaultTimeout = 10 * time.Second
+
+// Clientの設定（ゼロ値の項目は使わないか、デフォルト値を使う）
+type Options struct {
+	Timeout              time.Duration                    // コンテキストに期限が設定されていない呼び出しに設定する期限（0の場合はDefaultTimeout）
+	TransportCredentials credentials.TransportCredentials // nilの場合はTLSを使わずに接続する
+	Token                string                           // 空でなければ、Bearerトークンとしてすべての呼び出しに付ける
+	InsecureToken        bool                             // TLSを使わない接続でもトークンを送る（開発用）
+	SendInterval         time.Duration                    // ストリームでメッセージを送信する間隔（動作確認用、通常は0）
+
+	Logger  *slog.Logger // nilでなければ、呼び出しごとにメソッド・ステータスコード・処理時間を出力する
+	Metrics *Metrics     // nilでなければ、呼び出しのメトリクスを記録する
+	Tracing bool         // OpenTelemetryのスパンを作成し、トレースの情報をサーバーに送る
+
+	// 追加の接続オプション
+	// インターセプターを追加した場合は、このパッケージのインターセプターの内側で実行される
+	DialOptions []grpc.DialOption
+}
+
+// AlbumServiceのクライアント
+// 各メソッドはエラーをgRPCのステータスとして返し、詳細はIsNotFoundやFieldViolationsで確認できる
+// 複数のgoroutineから同時に呼び出しても安全
+type Client struct {
+	conn         *grpc.ClientConn // Newで接続した場合のみ設定する（Closeで閉じる）
+	rpc          pb.AlbumServiceClient
+	timeout      time.Duration
+	sendInterval time.Duration
+}
+
+// targetのサーバーに接続するクライアントを作成する関数
+// 接続は最初の呼び出しのときに確立する
+func New(target string, opts Options) (*Client, error) {
+	conn, err := grpc.NewClient(target, DialOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := FromConn(conn, opts)
+	c.conn = conn
+	return c, nil
+}
+
+// 作成済みの接続を使うクライアントを作成する関数
+// 接続の設定（DialOptionsで決まる項目）は使わず、TimeoutとSendIntervalだけを使う
+// 接続は呼び出し元で閉じること
+func FromConn(conn grpc.ClientConnInterface, opts Options) *Client {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Client{
+		rpc:          pb.NewAlbumServiceClient(conn),
+		timeout:      timeout,
+		sendInterval: opts.SendInterval,
+	}
+}
+
+// 設定に従ってインターセプターと認証情報を設定した接続オプションを返す関数
+// インターセプターはリクエストID、トレース、ログ、メトリクスの順に実行される
+func DialOptions(opts Options) []grpc.DialOption {
+	creds := opts.TransportCredentials
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+
+	// ログやトレースにリクエストIDを付けるため、リクエストIDを最初に設定する
+	unary := []grpc.UnaryClientInterceptor{RequestIDUnaryClientInterceptor()}
+	stream := []grpc.StreamClientInterceptor{RequestIDStreamClientInterceptor()}
+	if opts.Tracing {
+		unary = append(unary, tracing.UnaryClientInterceptor())
+		stream = append(stream, tracing.StreamClientInterceptor())
+	}
+	if opts.Logger != nil {
+		unary = append(unary, LoggingUnaryClientInterceptor(opts.Logger))
+		stream = append(stream, LoggingStreamClientInterceptor(opts.Logger))
+	}
+	if opts.Metrics != nil {
+		unary = append(unary, opts.Metrics.UnaryClientInterceptor())
+		stream = append(stream, opts.Metrics.StreamClientInterceptor())
+	}
+
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(unary...),
+		grpc.WithChainStreamInterceptor(stream...),
+	}
+	if opts.Token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.NewTokenCredentials(opts.Token, opts.InsecureToken)))
+	}
+
+	return append(dialOpts, opts.DialOptions...)
+}
+
+// Newで作成した接続を閉じるメソッド（FromConnで作成した場合は何もしない）
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// このパッケージにないRPCの呼び出し方をしたい場合に、生成されたクライアントを返すメソッド
+// 期限は設定されないため、呼び出し元で設定すること
+func (c *Client) Service() pb.AlbumServiceClient {
+	return c.rpc
+}
+
+// コンテキストに期限が設定されていなければ、デフォルトの期限を設定する
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// dだけ待つ関数
+// 待っている間にコンテキストがキャンセルされた場合はすぐに戻る
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+package albumclient
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/tracing"
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// ListAlbumsで決まった数のアルバムを返すだけのサーバー
+type listServer struct {
+	pb.UnimplementedAlbumServiceServer
+	count int
+}
+
+func (s *listServer) ListAlbums(req *pb.ListAlbumsRequest, stream pb.AlbumService_ListAlbumsServer) error {
+	for i := range s.count {
+		if err := stream.Send(&pb.ListAlbumsResponse{Album: &pb.Album{Id: string(rune('a' + i))}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 別のgoroutineから書き込まれるログを読めるようにするバッファ
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+type testClient struct {
+	*Client
+	logs     *syncBuffer
+	registry *prometheus.Registry
+	spans    *tracetest.InMemoryExporter
+	flush    func()
+}
+
+func newTestClient(t *testing.T, srv pb.AlbumServiceServer) *testClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterAlbumServiceServer(grpcServer, srv)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider(exporter, "albumclient-test", 1)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	logs := &syncBuffer{}
+	registry := prometheus.NewRegistry()
+	c, err := New("passthrough:///bufconn", Options{
+		Logger:  slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Metrics: NewMetrics(registry),
+		Tracing: true,
+		DialOptions: []grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return &testClient{
+		Client:   c,
+		logs:     logs,
+		registry: registry,
+		spans:    exporter,
+		flush:    func() { tp.ForceFlush(context.Background()) },
+	}
+}
+
+// grpc_client_handled_totalのうち、methodとcodeが一致する値を返すメソッド
+func (c *testClient) handled(t *testing.T, method, code string) float64 {
+	t.Helper()
+
+	families, err := c.registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "grpc_client_handled_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["grpc_method"] == method && labels["grpc_code"] == code {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+// ログ・メトリクス・スパンのすべてに、呼び出しの終了が1度だけ記録されるまで待つメソッド
+func (c *testClient) waitFinished(t *testing.T, method, code string) {
+	t.Helper()
+
+	var logged, counted, ended int
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		logged = strings.Count(c.logs.String(), `"method":"/album.AlbumService/`+method+`"`)
+		counted = int(c.handled(t, method, code))
+		c.flush()
+		ended = 0
+		for _, span := range c.spans.GetSpans() {
+			if span.Name == "album.AlbumService/"+method {
+				ended++
+			}
+		}
+		if logged > 0 && counted > 0 && ended > 0 {
+			break
+		}
+	}
+
+	if logged != 1 || !strings.Contains(c.logs.String(), `"code":"`+code+`"`) {
+		t.Errorf("logged %d times with code %s, want once: %s", logged, code, c.logs.String())
+	}
+	if counted != 1 {
+		t.Errorf("grpc_client_handled_total{grpc_code=%q} = %d, want 1", code, counted)
+	}
+	if ended != 1 {
+		t.Errorf("ended %d client spans, want 1", ended)
+	}
+}
+
+func TestListAlbumsFinishesStream(t *testing.T) {
+	t.Run("all albums received", func(t *testing.T) {
+		c := newTestClient(t, &listServer{count: 3})
+
+		var n int
+		err := c.ListAlbums(context.Background(), &pb.ListAlbumsRequest{}, func(*pb.Album) error {
+			n++
+			return nil
+		})
+		if err != nil || n != 3 {
+			t.Fatalf("ListAlbums() = %v after %d albums, want nil after 3", err, n)
+		}
+		// 受信し終えた後のキャンセルで、終了が2回記録されない
+		time.Sleep(50 * time.Millisecond)
+		c.waitFinished(t, "ListAlbums", "OK")
+	})
+
+	t.Run("stopped early", func(t *testing.T) {
+		c := newTestClient(t, &listServer{count: 3})
+
+		errStop := errors.New("stop")
+		err := c.ListAlbums(context.Background(), &pb.ListAlbumsRequest{}, func(*pb.Album) error {
+			return errStop
+		})
+		if !errors.Is(err, errStop) {
+			t.Fatalf("ListAlbums() error = %v, want %v", err, errStop)
+		}
+		// 最後まで受信しなくても、コンテキストのキャンセルで終了が記録される
+		c.waitFinished(t, "ListAlbums", "Canceled")
+	})
+}
@@ -0,0 +1,54 @@
+package albumclient
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 指定したアルバムが存在しなかったか判定する関数
+func IsNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+// 同じタイトルとアーティストのアルバムが既に登録されていたか判定する関数
+func IsAlreadyExists(err error) bool {
+	return status.Code(err) == codes.AlreadyExists
+}
+
+// リクエストの内容が不正だった場合に、不正なフィールドとその理由を返す関数
+// それ以外のエラーの場合はnilを返す
+func FieldViolations(err error) []*errdetails.BadRequest_FieldViolation {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		return nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, d.GetFieldViolations()...)
+		}
+	}
+
+	return violations
+}
+
+// 呼び出しの頻度の制限を超えた場合に、再試行するまで待つべき時間を返す関数
+// サーバーが待ち時間を返さなかった場合はfalseを返す
+func RetryDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.RetryInfo); ok {
+			return d.GetRetryDelay().AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,199 @@
+package albumclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// リクエストIDを受け渡すメタデータのキー（サーバーと同じキーを使う）
+const RequestIDKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// 呼び出しに使うリクエストIDをコンテキストに設定する関数
+// 設定しなかった場合は、呼び出しごとに新しいリクエストIDを採番する
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// Unary RPCのメタデータにリクエストIDを付けるインターセプター
+// サーバーは受け取ったリクエストIDをログに付けるため、クライアントとサーバーのログを突き合わせられる
+func RequestIDUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withOutgoingRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// ストリーム形式のRPCのメタデータにリクエストIDを付けるインターセプター
+func RequestIDStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withOutgoingRequestID(ctx), desc, cc, method, opts...)
+	}
+}
+
+// 送信するメタデータにリクエストIDがなければ付ける関数
+func withOutgoingRequestID(ctx context.Context) context.Context {
+	if requestID(ctx) != "" {
+		return ctx // 呼び出し元がメタデータで設定済み
+	}
+
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	if !ok || id == "" {
+		b := make([]byte, 16)
+		rand.Read(b) // crypto/randのReadはエラーを返さない
+		id = hex.EncodeToString(b)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, RequestIDKey, id)
+}
+
+// 送信するメタデータのリクエストIDを返す関数
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if values := md.Get(RequestIDKey); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Unary RPCの呼び出しが終わるたびに、メソッド・ステータスコード・処理時間をログに出力するインターセプター
+// 成功した呼び出しはDebug、失敗した呼び出しはWarnのレベルで出力する
+func LoggingUnaryClientInterceptor(logger *slog.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCall(ctx, logger, method, "unary", start, err)
+
+		return err
+	}
+}
+
+// ストリーム形式のRPCが終わったときに、送受信したメッセージの数も含めてログに出力するインターセプター
+// ストリームの終了はRecvMsgがエラーを返したとき（レスポンスが1つのRPCは受信したとき）か、
+// 最後まで受信せずにコンテキストがキャンセルされたときに判定する
+func LoggingStreamClientInterceptor(logger *slog.Logger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		kind := streamKind(desc)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(ctx, logger, method, kind, start, err)
+			return nil, err
+		}
+
+		ms := &monitoredClientStream{ClientStream: cs, desc: desc}
+		ms.onFinish = func(err error) {
+			logCall(ctx, logger, method, kind, start, err,
+				slog.Int64("sent", ms.sent.Load()), slog.Int64("received", ms.received.Load()))
+		}
+		ms.watch(ctx)
+		return ms, nil
+	}
+}
+
+func logCall(ctx context.Context, logger *slog.Logger, method, kind string, start time.Time, err error, attrs ...slog.Attr) {
+	code := status.Code(err)
+	level := slog.LevelDebug
+	if code != codes.OK {
+		level = slog.LevelWarn
+	}
+
+	attrs = append([]slog.Attr{
+		slog.String("method", method),
+		slog.String("kind", kind),
+		slog.String("request_id", requestID(ctx)),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}, attrs...)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+
+	logger.LogAttrs(ctx, level, "rpc finished", attrs...)
+}
+
+// 送受信したメッセージを数え、ストリームが終わったときにonFinishを呼ぶClientStream
+type monitoredClientStream struct {
+	grpc.ClientStream
+	desc *grpc.StreamDesc
+
+	// 送信と受信は別のgoroutineから呼ばれることがあるため、アトミックに数える
+	sent     atomic.Int64
+	received atomic.Int64
+
+	onFinish func(err error) // 1度だけ呼ばれる（io.EOFで終わった場合はnil）
+	once     sync.Once
+	stop     func() bool // watchで登録したコンテキストの監視を解除する
+}
+
+// 呼び出し元が最後まで受信せずにやめた場合もonFinishが呼ばれるよう、コンテキストのキャンセルを監視するメソッド
+// onFinishを設定した後、ストリームを返す前に呼ぶこと
+func (s *monitoredClientStream) watch(ctx context.Context) {
+	s.stop = context.AfterFunc(ctx, func() {
+		s.once.Do(func() { s.onFinish(status.FromContextError(ctx.Err()).Err()) })
+	})
+}
+
+func (s *monitoredClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.Add(1)
+	}
+	return err
+}
+
+func (s *monitoredClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.finish(statusError(err))
+		return err
+	}
+
+	s.received.Add(1)
+	// サーバーからのレスポンスが1つだけのRPCは、受信した時点で終了する
+	if !s.desc.ServerStreams {
+		s.finish(nil)
+	}
+	return nil
+}
+
+func (s *monitoredClientStream) finish(err error) {
+	if s.stop != nil {
+		s.stop()
+	}
+	s.once.Do(func() { s.onFinish(err) })
+}
+
+// io.EOFなどgRPCのステータスでないエラーは、正常な終了として扱う
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return nil
+}
+
+// ストリームの種類を表す文字列を返す関数
+func streamKind(desc *grpc.StreamDesc) string {
+	switch {
+	case desc.ClientStreams && desc.ServerStreams:
+		return "bidi_stream"
+	case desc.ClientStreams:
+		return "client_stream"
+	case desc.ServerStreams:
+		return "server_stream"
+	default:
+		return "unary"
+	}
+}
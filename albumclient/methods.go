@@ -0,0 +1,205 @@
+package albumclient
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// IDで指定したアルバムを取得するメソッド
+func (c *Client) GetAlbum(ctx context.Context, id string) (*pb.Album, error) {
+	return c.getAlbum(ctx, &pb.GetAlbumRequest{Id: id})
+}
+
+// タイトルで指定したアルバムを取得するメソッド
+// 同じタイトルのアルバムが複数ある場合は最初に登録されたものを返す
+func (c *Client) GetAlbumByTitle(ctx context.Context, title string) (*pb.Album, error) {
+	return c.getAlbum(ctx, &pb.GetAlbumRequest{Title: title})
+}
+
+func (c *Client) getAlbum(ctx context.Context, req *pb.GetAlbumRequest) (*pb.Album, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.GetAlbum(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Album, nil
+}
+
+// 条件に一致するアルバムを受信するたびにfnを呼ぶメソッド
+// fnがエラーを返した場合は受信をやめ、そのエラーを返す
+func (c *Client) ListAlbums(ctx context.Context, req *pb.ListAlbumsRequest, fn func(*pb.Album) error) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel() // 途中でやめた場合もストリームを終了させる
+
+	stream, err := c.rpc.ListAlbums(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(resp.Album); err != nil {
+			return err
+		}
+	}
+}
+
+// 絞り込み条件や全文検索のキーワードで、アルバムを1ページ分取得するメソッド
+// 次のページはレスポンスのnext_page_tokenをpage_tokenに設定して取得する
+func (c *Client) SearchAlbums(ctx context.Context, req *pb.SearchAlbumsRequest) (*pb.SearchAlbumsResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.rpc.SearchAlbums(ctx, req)
+}
+
+// タイトルで指定したアルバムの数と、通貨ごとの合計金額を取得するメソッド
+func (c *Client) GetTotalAmount(ctx context.Context, titles []string) (*pb.GetTotalAmountResponse, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	stream, err := c.rpc.GetTotalAmount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, title := range titles {
+		if i > 0 {
+			if err := sleep(ctx, c.sendInterval); err != nil {
+				return nil, err
+			}
+		}
+
+		err := stream.Send(&pb.GetTotalAmountRequest{Title: title})
+		if errors.Is(err, io.EOF) {
+			break // サーバーがストリームを終了した（エラー内容はCloseAndRecvで受け取る）
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// アルバムを順に登録し、サーバーからの通知を受信するたびにfnを呼ぶメソッド
+// 登録できないアルバムがあった場合は、サーバーがストリームを終了するため残りのアルバムは送信しない
+// fnは受信用のgoroutineから順に呼ばれる
+func (c *Client) UploadAlbums(ctx context.Context, albums []*pb.Album, fn func(*pb.UploadAndNotifyResponse)) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	stream, err := c.rpc.UploadAndNotify(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		recvErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				recvErr = err
+				return
+			}
+			if fn != nil {
+				fn(resp)
+			}
+		}
+	}()
+
+	sendErr := c.sendAlbums(ctx, stream, albums)
+	if sendErr != nil {
+		cancel() // ストリームを閉じられていないため、キャンセルして受信側も終了させる
+	}
+	wg.Wait()
+
+	// サーバーが返したエラー（受信側）の方が原因を表しているため優先する
+	if recvErr != nil {
+		return recvErr
+	}
+	return sendErr
+}
+
+func (c *Client) sendAlbums(ctx context.Context, stream pb.AlbumService_UploadAndNotifyClient, albums []*pb.Album) error {
+	for i, album := range albums {
+		if i > 0 {
+			if err := sleep(ctx, c.sendInterval); err != nil {
+				return err
+			}
+		}
+
+		err := stream.Send(&pb.UploadAndNotifyRequest{Album: album})
+		if errors.Is(err, io.EOF) {
+			return nil // サーバーがストリームを終了した（エラー内容はRecvで受け取る）
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return stream.CloseSend()
+}
+
+// アルバムを新規作成し、IDと作成日時が設定されたアルバムを返すメソッド
+func (c *Client) CreateAlbum(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.rpc.CreateAlbum(ctx, &pb.CreateAlbumRequest{Album: album})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Album, nil
+}
+
+// album.Idで指定したアルバムのうち、pathsで指定したフィールドだけを更新して更新後のアルバムを返すメソッド
+// pathsを省略した場合は、サーバーが設定するフィールド以外のすべてを更新する
+func (c *Client) UpdateAlbum(ctx context.Context, album *pb.Album, paths ...string) (*pb.Album, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	req := &pb.UpdateAlbumRequest{Album: album}
+	if len(paths) > 0 {
+		req.UpdateMask = &fieldmaskpb.FieldMask{Paths: paths}
+	}
+	resp, err := c.rpc.UpdateAlbum(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Album, nil
+}
+
+// IDで指定したアルバムを削除するメソッド
+func (c *Client) DeleteAlbum(ctx context.Context, id string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	_, err := c.rpc.DeleteAlbum(ctx, &pb.DeleteAlbumRequest{Id: id})
+	return err
+}
@@ -0,0 +1,132 @@
+package albumclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// クライアントから呼び出したRPCのメトリクス
+// ラベルのgrpc_typeはunary、client_stream、server_stream、bidi_streamのいずれか
+type Metrics struct {
+	started  *prometheus.CounterVec   // 開始したRPCの数
+	handled  *prometheus.CounterVec   // 終了したRPCの数（ステータスコード別）
+	duration *prometheus.HistogramVec // RPCの開始から終了までの時間
+	received *prometheus.CounterVec   // 受信したメッセージの数
+	sent     *prometheus.CounterVec   // 送信したメッセージの数
+}
+
+// クライアントのメトリクスを作成し、regに登録する関数
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+
+	m := &Metrics{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_started_total",
+			Help: "Total number of RPCs started on the client.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_handled_total",
+			Help: "Total number of RPCs completed by the client, regardless of success or failure.",
+		}, append(labels, "grpc_code")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_client_handling_seconds",
+			Help:    "Time taken by RPCs called by the client.",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, labels),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_msg_received_total",
+			Help: "Total number of messages received by the client.",
+		}, labels),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_client_msg_sent_total",
+			Help: "Total number of messages sent by the client.",
+		}, labels),
+	}
+	reg.MustRegister(m.started, m.handled, m.duration, m.received, m.sent)
+
+	return m
+}
+
+// Unary RPCのメトリクスを記録するインターセプター
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		labels := m.labels("unary", method)
+		m.started.WithLabelValues(labels...).Inc()
+		m.sent.WithLabelValues(labels...).Inc()
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if err == nil {
+			m.received.WithLabelValues(labels...).Inc()
+		}
+		m.finish(labels, start, err)
+
+		return err
+	}
+}
+
+// ストリーム形式のRPCのメトリクスを記録するインターセプター
+// 送受信したメッセージはその都度数え、処理時間と結果はストリームが終わったとき（最後まで受信せずにやめた場合はコンテキストのキャンセル時）に記録する
+func (m *Metrics) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		labels := m.labels(streamKind(desc), method)
+		m.started.WithLabelValues(labels...).Inc()
+
+		start := time.Now()
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			m.finish(labels, start, err)
+			return nil, err
+		}
+
+		counting := &countingClientStream{
+			monitoredClientStream: monitoredClientStream{
+				ClientStream: cs,
+				desc:         desc,
+				onFinish:     func(err error) { m.finish(labels, start, err) },
+			},
+			sent:     m.sent.WithLabelValues(labels...),
+			received: m.received.WithLabelValues(labels...),
+		}
+		counting.watch(ctx)
+		return counting, nil
+	}
+}
+
+func (m *Metrics) labels(kind, fullMethod string) []string {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return []string{kind, service, method}
+}
+
+func (m *Metrics) finish(labels []string, start time.Time, err error) {
+	m.handled.WithLabelValues(append(labels, status.Code(err).String())...).Inc()
+	m.duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+}
+
+// 送受信したメッセージをメトリクスのカウンターでも数えるClientStream
+type countingClientStream struct {
+	monitoredClientStream
+	sent     prometheus.Counter
+	received prometheus.Counter
+}
+
+func (s *countingClientStream) SendMsg(m interface{}) error {
+	err := s.monitoredClientStream.SendMsg(m)
+	if err == nil {
+		s.sent.Inc()
+	}
+	return err
+}
+
+func (s *countingClientStream) RecvMsg(m interface{}) error {
+	err := s.monitoredClientStream.RecvMsg(m)
+	if err == nil {
+		s.received.Inc()
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JWTのHMAC鍵の最短の長さ
+const minSecretLength = 32
+
+// APIキーファイルの1件分
+type APIKey struct {
+	Key     string   `yaml:"key"`
+	Subject string   `yaml:"subject"`
+	Roles   []string `yaml:"roles"`
+}
+
+// Bearerトークンを検証して呼び出し元を特定する構造体
+// 静的なAPIキーと、HMACで署名されたJWTの両方に対応する
+type Authenticator struct {
+	apiKeys map[[sha256.Size]byte]*Identity // APIキーのハッシュ値から呼び出し元を引く
+
+	jwtSecret   []byte // 空の場合はJWTを受け付けない
+	jwtIssuer   string
+	jwtAudience string
+
+	now func() time.Time
+}
+
+// Authenticatorの設定
+type Options struct {
+	APIKeys     []APIKey
+	JWTSecret   []byte
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// Authenticatorを作成する関数
+func NewAuthenticator(opts Options) (*Authenticator, error) {
+	if len(opts.APIKeys) == 0 && len(opts.JWTSecret) == 0 {
+		return nil, errors.New("either api keys or a jwt secret must be configured")
+	}
+	if len(opts.JWTSecret) > 0 && len(opts.JWTSecret) < minSecretLength {
+		return nil, fmt.Errorf("jwt secret must be at least %d bytes", minSecretLength)
+	}
+
+	a := &Authenticator{
+		apiKeys:     make(map[[sha256.Size]byte]*Identity),
+		jwtSecret:   opts.JWTSecret,
+		jwtIssuer:   opts.JWTIssuer,
+		jwtAudience: opts.JWTAudience,
+		now:         time.Now,
+	}
+	for i, key := range opts.APIKeys {
+		if key.Key == "" || key.Subject == "" {
+			return nil, fmt.Errorf("api key %d: key and subject must be set", i)
+		}
+		// キーそのものではなくハッシュ値で引くことで、比較にかかる時間からキーを推測されないようにする
+		hash := sha256.Sum256([]byte(key.Key))
+		if _, ok := a.apiKeys[hash]; ok {
+			return nil, fmt.Errorf("api key %d: duplicate key", i)
+		}
+		a.apiKeys[hash] = &Identity{Subject: key.Subject, Roles: key.Roles, Kind: KindAPIKey}
+	}
+
+	return a, nil
+}
+
+// トークンを検証して呼び出し元を返すメソッド
+// 登録済みのAPIキーに一致するトークンはAPIキー、それ以外でピリオドで区切られた3つの部分からなるトークンはJWTとして扱う
+// （ピリオドを2つ含むAPIキーをJWTとして扱わないよう、先にAPIキーを引く）
+func (a *Authenticator) Authenticate(token string) (*Identity, error) {
+	if id, ok := a.apiKeys[sha256.Sum256([]byte(token))]; ok {
+		return id, nil
+	}
+
+	if strings.Count(token, ".") == 2 {
+		if len(a.jwtSecret) == 0 {
+			return nil, fmt.Errorf("%w: jwt is not accepted", ErrInvalidToken)
+		}
+		claims, err := ParseJWT(token, a.jwtSecret, a.jwtIssuer, a.jwtAudience, a.now())
+		if err != nil {
+			return nil, err
+		}
+		return &Identity{Subject: claims.Subject, Roles: claims.Roles, Kind: KindJWT}, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// YAMLのAPIキーファイルを読み込む関数
+func LoadAPIKeys(path string) ([]APIKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []APIKey
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&keys); err != nil {
+		return nil, fmt.Errorf("api keys file %s: %w", path, err)
+	}
+
+	return keys, nil
+}
+
+// JWTのHMAC鍵をファイルから読み込む関数
+// 末尾の改行は鍵に含めない
+func LoadSecret(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSpace(data), nil
+}
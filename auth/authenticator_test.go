@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	a, err := NewAuthenticator(Options{
+		APIKeys: []APIKey{
+			{Key: "key-alice", Subject: "alice", Roles: []string{"admin"}},
+			// ピリオドを2つ含むが、APIキーとして登録されているのでJWTとしては扱わない
+			{Key: "key.with.dots", Subject: "bob", Roles: []string{"reader"}},
+		},
+		JWTSecret:   testSecret,
+		JWTIssuer:   "album-auth",
+		JWTAudience: "album-api",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.now = func() time.Time { return now }
+
+	jwt, err := SignJWT(testSecret, Claims{
+		Subject:   "carol",
+		Issuer:    "album-auth",
+		Audience:  Audience{"album-api"},
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		Roles:     []string{"writer"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, err := SignJWT(testSecret, Claims{
+		Subject:   "carol",
+		Issuer:    "album-auth",
+		Audience:  Audience{"album-api"},
+		ExpiresAt: now.Add(-time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *Identity
+		wantErr error
+	}{
+		{name: "api key", token: "key-alice", want: &Identity{Subject: "alice", Roles: []string{"admin"}, Kind: KindAPIKey}},
+		{name: "api key with dots", token: "key.with.dots", want: &Identity{Subject: "bob", Roles: []string{"reader"}, Kind: KindAPIKey}},
+		{name: "jwt", token: jwt, want: &Identity{Subject: "carol", Roles: []string{"writer"}, Kind: KindJWT}},
+		{name: "unknown api key", token: "key-mallory", wantErr: ErrUnknownKey},
+		{name: "expired jwt", token: expired, wantErr: ErrTokenExpired},
+		{name: "malformed jwt", token: "a.b.c", wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.Authenticate(tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Authenticate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if got.Subject != tt.want.Subject || got.Kind != tt.want.Kind || !slices.Equal(got.Roles, tt.want.Roles) {
+				t.Errorf("Authenticate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// JWTの鍵が設定されていない場合はJWTを受け付けない
+func TestAuthenticateWithoutJWTSecret(t *testing.T) {
+	a, err := NewAuthenticator(Options{APIKeys: []APIKey{{Key: "key-alice", Subject: "alice"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := SignJWT(testSecret, Claims{Subject: "carol", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Authenticate(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestNewAuthenticatorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "nothing configured", opts: Options{}, want: "either api keys or a jwt secret"},
+		{name: "short secret", opts: Options{JWTSecret: []byte("short")}, want: "at least"},
+		{name: "empty key", opts: Options{APIKeys: []APIKey{{Subject: "alice"}}}, want: "key and subject must be set"},
+		{name: "empty subject", opts: Options{APIKeys: []APIKey{{Key: "key-alice"}}}, want: "key and subject must be set"},
+		{name: "duplicate key", opts: Options{APIKeys: []APIKey{{Key: "k", Subject: "a"}, {Key: "k", Subject: "b"}}}, want: "duplicate key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAuthenticator(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewAuthenticator() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	keys, err := LoadAPIKeys("api_keys.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) == 0 {
+		t.Fatal("LoadAPIKeys() returned no keys")
+	}
+	if _, err := NewAuthenticator(Options{APIKeys: keys}); err != nil {
+		t.Errorf("NewAuthenticator() with the example keys error = %v", err)
+	}
+
+	// 知らないキーは誤記として扱う
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	if err := os.WriteFile(path, []byte("- key: k\n  subject: s\n  role: admin\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAPIKeys(path); err == nil {
+		t.Error("LoadAPIKeys() with an unknown field error = nil, want error")
+	}
+}
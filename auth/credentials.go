@@ -0,0 +1,24 @@
+package auth
+
+import "context"
+
+// RPCごとにBearerトークンをメタデータに付けるcredentials.PerRPCCredentialsの実装
+// grpc.WithPerRPCCredentialsでクライアントに設定する
+type TokenCredentials struct {
+	token         string
+	allowInsecure bool
+}
+
+// トークンを送るPerRPCCredentialsを作成する関数
+// allowInsecureがfalseの場合、TLSを使わない接続ではトークンを送らない（RPCはエラーになる）
+func NewTokenCredentials(token string, allowInsecure bool) *TokenCredentials {
+	return &TokenCredentials{token: token, allowInsecure: allowInsecure}
+}
+
+func (c *TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+func (c *TokenCredentials) RequireTransportSecurity() bool {
+	return !c.allowInsecure
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// トークンの種類
+const (
+	KindAPIKey = "api_key"
+	KindJWT    = "jwt"
+)
+
+// 認証された呼び出し元
+type Identity struct {
+	Subject string   // 呼び出し元を識別する名前（APIキーの持ち主、またはJWTのsub）
+	Roles   []string // 呼び出し元に与えられたロール
+	Kind    string   // 認証に使われたトークンの種類（KindAPIKeyまたはKindJWT）
+}
+
+// 呼び出し元が指定したロールを持っているか確認するメソッド
+func (id *Identity) HasRole(role string) bool {
+	return slices.Contains(id.Roles, role)
+}
+
+type identityKey struct{}
+
+// 呼び出し元をコンテキストに設定する関数
+func NewContext(ctx context.Context, id *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// コンテキストから呼び出し元を取り出す関数
+// 認証されていないRPCでは、falseを返す
+func FromContext(ctx context.Context) (*Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(*Identity)
+	return id, ok
+}
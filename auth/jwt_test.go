@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"hash"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// 任意のヘッダーとペイロードでJWTを作成する関数
+// 不正なトークンを作れるよう、SignJWTを使わずに組み立てる
+func makeJWT(t *testing.T, alg string, newHash func() hash.Hash, secret []byte, payload any) string {
+	t.Helper()
+
+	header, err := json.Marshal(jwtHeader{Alg: alg, Typ: "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(body)
+	if newHash == nil {
+		return signingInput + "."
+	}
+	return signingInput + "." + encodeSegment(sign(newHash, secret, signingInput))
+}
+
+func TestParseJWT(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	valid := Claims{Subject: "alice", ExpiresAt: now.Add(time.Hour).Unix(), Roles: []string{"admin"}}
+	with := func(modify func(*Claims)) Claims {
+		c := valid
+		modify(&c)
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		issuer   string
+		audience string
+		wantErr  error // nilの場合は検証に成功する
+	}{
+		{name: "HS256", token: makeJWT(t, "HS256", sha256.New, testSecret, valid)},
+		{name: "HS384", token: makeJWT(t, "HS384", sha512.New384, testSecret, valid)},
+		{name: "HS512", token: makeJWT(t, "HS512", sha512.New, testSecret, valid)},
+
+		// 署名
+		{name: "wrong secret", token: makeJWT(t, "HS256", sha256.New, []byte("another-secret-another-secret-00"), valid), wantErr: ErrInvalidToken},
+		{name: "tampered payload", token: tamper(t, makeJWT(t, "HS256", sha256.New, testSecret, valid)), wantErr: ErrInvalidToken},
+		{name: "signed with another algorithm", token: relabel(t, makeJWT(t, "HS256", sha256.New, testSecret, valid), "HS512"), wantErr: ErrInvalidToken},
+
+		// アルゴリズム
+		{name: "alg none", token: makeJWT(t, "none", nil, nil, valid), wantErr: ErrInvalidToken},
+		{name: "alg RS256", token: makeJWT(t, "RS256", sha256.New, testSecret, valid), wantErr: ErrInvalidToken},
+
+		// 有効期限
+		{name: "expired", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.ExpiresAt = now.Add(-time.Minute).Unix() })), wantErr: ErrTokenExpired},
+		{name: "expired within clock skew", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.ExpiresAt = now.Add(-clockSkew / 2).Unix() }))},
+		{name: "missing exp", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.ExpiresAt = 0 })), wantErr: ErrInvalidToken},
+		{name: "missing sub", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.Subject = "" })), wantErr: ErrInvalidToken},
+
+		// 有効期間の開始
+		{name: "not valid yet", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.NotBefore = now.Add(time.Minute).Unix() })), wantErr: ErrInvalidToken},
+		{name: "nbf within clock skew", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.NotBefore = now.Add(clockSkew / 2).Unix() }))},
+		{name: "nbf in the past", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.NotBefore = now.Add(-time.Minute).Unix() }))},
+
+		// 発行者
+		{name: "issuer matches", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.Issuer = "album-auth" })), issuer: "album-auth"},
+		{name: "issuer mismatch", token: makeJWT(t, "HS256", sha256.New, testSecret, with(func(c *Claims) { c.Issuer = "other" })), issuer: "album-auth", wantErr: ErrInvalidToken},
+		{name: "issuer missing", token: makeJWT(t, "HS256", sha256.New, testSecret, valid), issuer: "album-auth", wantErr: ErrInvalidToken},
+
+		// 対象者（文字列と配列のどちらでもよい）
+		{name: "audience string", token: makeJWT(t, "HS256", sha256.New, testSecret, withRawAudience(valid, "album-api")), audience: "album-api"},
+		{name: "audience array", token: makeJWT(t, "HS256", sha256.New, testSecret, withRawAudience(valid, []string{"other-api", "album-api"})), audience: "album-api"},
+		{name: "audience string mismatch", token: makeJWT(t, "HS256", sha256.New, testSecret, withRawAudience(valid, "other-api")), audience: "album-api", wantErr: ErrInvalidToken},
+		{name: "audience array mismatch", token: makeJWT(t, "HS256", sha256.New, testSecret, withRawAudience(valid, []string{"other-api"})), audience: "album-api", wantErr: ErrInvalidToken},
+		{name: "audience missing", token: makeJWT(t, "HS256", sha256.New, testSecret, valid), audience: "album-api", wantErr: ErrInvalidToken},
+		{name: "audience not checked", token: makeJWT(t, "HS256", sha256.New, testSecret, withRawAudience(valid, []string{"other-api"}))},
+
+		// 形式
+		{name: "two segments", token: "abc.def", wantErr: ErrInvalidToken},
+		{name: "not base64", token: "!!!.???.***", wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseJWT(tt.token, testSecret, tt.issuer, tt.audience, now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParseJWT() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJWT() error = %v", err)
+			}
+			if claims.Subject != "alice" || !slices.Equal(claims.Roles, []string{"admin"}) {
+				t.Errorf("ParseJWT() claims = %+v", claims)
+			}
+		})
+	}
+}
+
+// SignJWTで作成したトークンはParseJWTで検証できる
+func TestSignJWT(t *testing.T) {
+	now := time.Now()
+	want := Claims{
+		Subject:   "alice",
+		Issuer:    "album-auth",
+		Audience:  Audience{"album-api", "admin-api"},
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		Roles:     []string{"reader"},
+	}
+
+	token, err := SignJWT(testSecret, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseJWT(token, testSecret, "album-auth", "admin-api", now)
+	if err != nil {
+		t.Fatalf("ParseJWT() error = %v", err)
+	}
+	if got.Subject != want.Subject || !slices.Equal(got.Audience, want.Audience) || !slices.Equal(got.Roles, want.Roles) {
+		t.Errorf("ParseJWT() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAudienceJSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want Audience
+	}{
+		{json: `"album-api"`, want: Audience{"album-api"}},
+		{json: `["album-api","admin-api"]`, want: Audience{"album-api", "admin-api"}},
+	}
+	for _, tt := range tests {
+		var got Audience
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", tt.json, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, got, tt.want)
+		}
+		// 読み込んだ形式のまま書き出す
+		data, err := json.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.json {
+			t.Errorf("Marshal(%v) = %s, want %s", got, data, tt.json)
+		}
+	}
+
+	var got Audience
+	if err := json.Unmarshal([]byte(`123`), &got); err == nil {
+		t.Error("Unmarshal(123) error = nil, want error")
+	}
+}
+
+// audをJSONの値のまま指定したペイロードを返す関数
+func withRawAudience(claims Claims, aud any) map[string]any {
+	data, _ := json.Marshal(claims)
+	var payload map[string]any
+	json.Unmarshal(data, &payload)
+	payload["aud"] = aud
+	return payload
+}
+
+// 署名を変えずにペイロードのsubを書き換えたトークンを返す関数
+func tamper(t *testing.T, token string) string {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	var payload map[string]any
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		t.Fatal(err)
+	}
+	payload["sub"] = "mallory"
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts[1] = encodeSegment(data)
+	return strings.Join(parts, ".")
+}
+
+// 署名を変えずにヘッダーのalgを書き換えたトークンを返す関数
+func relabel(t *testing.T, token, alg string) string {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	header, err := json.Marshal(jwtHeader{Alg: alg, Typ: "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts[0] = encodeSegment(header)
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrPermissionDenied = errors.New("permission denied") // 呼び出し元がメソッドを呼び出すロールを持っていない
+
+// 認証済みであればどのロールでも許可することを表すロール名
+const AnyRole = "*"
+
+// メソッドごとに呼び出しを許可するロールを定めたポリシー
+//
+// メソッドは"/album.AlbumService/GetAlbum"のようなgRPCのフルメソッド名で指定する
+// "/album.AlbumService/"のように"/"で終わる場合は、個別の指定がないサービス内のすべてのメソッドに一致する
+// どの規則にも一致しないメソッドの呼び出しは拒否する
+type Policy struct {
+	methods  map[string][]string // フルメソッド名ごとの許可するロール
+	services map[string][]string // サービス名（"/"で終わる）ごとの許可するロール
+}
+
+// ポリシーファイルの規則
+type Rule struct {
+	Methods []string `yaml:"methods"`
+	Roles   []string `yaml:"roles"`
+}
+
+// 規則の一覧からポリシーを作成する関数
+// 同じメソッドが複数の規則に含まれる場合は、許可するロールを合わせる
+func NewPolicy(rules []Rule) (*Policy, error) {
+	p := &Policy{
+		methods:  make(map[string][]string),
+		services: make(map[string][]string),
+	}
+
+	for i, rule := range rules {
+		if len(rule.Methods) == 0 || len(rule.Roles) == 0 {
+			return nil, fmt.Errorf("rule %d: methods and roles must be set", i)
+		}
+		for _, method := range rule.Methods {
+			service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+			if !strings.HasPrefix(method, "/") || !ok || service == "" || strings.Contains(name, "/") {
+				return nil, fmt.Errorf("rule %d: invalid method %q (expected /package.Service/Method or /package.Service/)", i, method)
+			}
+			if name == "" {
+				p.services[method] = append(p.services[method], rule.Roles...)
+			} else {
+				p.methods[method] = append(p.methods[method], rule.Roles...)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// YAMLのポリシーファイルを読み込む関数
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+
+	p, err := NewPolicy(file.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// 呼び出し元がメソッドを呼び出せるか確認するメソッド
+func (p *Policy) Authorize(fullMethod string, id *Identity) error {
+	roles, ok := p.methods[fullMethod]
+	if !ok {
+		service, _, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+		roles, ok = p.services["/"+service+"/"]
+	}
+	if !ok {
+		return fmt.Errorf("%w: no rule for %s", ErrPermissionDenied, fullMethod)
+	}
+
+	if slices.Contains(roles, AnyRole) {
+		return nil
+	}
+	for _, role := range roles {
+		if id.HasRole(role) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s requires one of roles %v", ErrPermissionDenied, fullMethod, roles)
+}
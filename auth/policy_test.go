@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicyAuthorize(t *testing.T) {
+	p, err := NewPolicy([]Rule{
+		{Methods: []string{"/album.AlbumService/GetAlbum", "/album.AlbumService/ListAlbums"}, Roles: []string{"reader", "editor"}},
+		{Methods: []string{"/album.AlbumService/DeleteAlbum"}, Roles: []string{"editor"}},
+		// 同じメソッドを含む規則は許可するロールを合わせる
+		{Methods: []string{"/album.AlbumService/DeleteAlbum"}, Roles: []string{"admin"}},
+		{Methods: []string{"/album.AdminService/"}, Roles: []string{"admin"}},
+		// サービス単位の規則より個別のメソッドの規則を優先する
+		{Methods: []string{"/album.AdminService/Status"}, Roles: []string{AnyRole}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := &Identity{Subject: "alice", Roles: []string{"reader"}}
+	editor := &Identity{Subject: "bob", Roles: []string{"editor"}}
+	admin := &Identity{Subject: "carol", Roles: []string{"admin"}}
+	nobody := &Identity{Subject: "dave"}
+
+	tests := []struct {
+		method string
+		id     *Identity
+		allow  bool
+	}{
+		{method: "/album.AlbumService/GetAlbum", id: reader, allow: true},
+		{method: "/album.AlbumService/GetAlbum", id: editor, allow: true},
+		{method: "/album.AlbumService/GetAlbum", id: admin, allow: false},
+		{method: "/album.AlbumService/ListAlbums", id: nobody, allow: false},
+		{method: "/album.AlbumService/DeleteAlbum", id: reader, allow: false},
+		{method: "/album.AlbumService/DeleteAlbum", id: editor, allow: true},
+		{method: "/album.AlbumService/DeleteAlbum", id: admin, allow: true},
+		{method: "/album.AdminService/Reset", id: admin, allow: true},
+		{method: "/album.AdminService/Reset", id: editor, allow: false},
+		{method: "/album.AdminService/Status", id: nobody, allow: true},
+		// どの規則にも一致しないメソッドは拒否する
+		{method: "/album.AlbumService/CreateAlbum", id: admin, allow: false},
+		{method: "/other.Service/GetAlbum", id: admin, allow: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+"/"+tt.id.Subject, func(t *testing.T) {
+			err := p.Authorize(tt.method, tt.id)
+			if tt.allow && err != nil {
+				t.Errorf("Authorize() error = %v, want nil", err)
+			}
+			if !tt.allow && !errors.Is(err, ErrPermissionDenied) {
+				t.Errorf("Authorize() error = %v, want %v", err, ErrPermissionDenied)
+			}
+		})
+	}
+}
+
+// 規則のないポリシーはすべての呼び出しを拒否する
+func TestPolicyDefaultDeny(t *testing.T) {
+	p, err := NewPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := &Identity{Subject: "alice", Roles: []string{"admin", AnyRole}}
+	if err := p.Authorize("/album.AlbumService/GetAlbum", id); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Authorize() error = %v, want %v", err, ErrPermissionDenied)
+	}
+}
+
+func TestNewPolicyInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{name: "no methods", rule: Rule{Roles: []string{"reader"}}},
+		{name: "no roles", rule: Rule{Methods: []string{"/album.AlbumService/GetAlbum"}}},
+		{name: "no leading slash", rule: Rule{Methods: []string{"album.AlbumService/GetAlbum"}, Roles: []string{"reader"}}},
+		{name: "no method", rule: Rule{Methods: []string{"/album.AlbumService"}, Roles: []string{"reader"}}},
+		{name: "no service", rule: Rule{Methods: []string{"//GetAlbum"}, Roles: []string{"reader"}}},
+		{name: "too many parts", rule: Rule{Methods: []string{"/album.AlbumService/GetAlbum/x"}, Roles: []string{"reader"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPolicy([]Rule{tt.rule}); err == nil {
+				t.Error("NewPolicy() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	p, err := LoadPolicy("policy.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	editor := &Identity{Subject: "bob", Roles: []string{"editor"}}
+	if err := p.Authorize("/album.AlbumService/DeleteAlbum", editor); err != nil {
+		t.Errorf("Authorize() error = %v, want nil", err)
+	}
+
+	// 知らないキーは誤記として扱う
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("rules:\n  - method: [/album.AlbumService/GetAlbum]\n    roles: [reader]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadPolicy(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("LoadPolicy() error = %v, want error mentioning %s", err, path)
+	}
+}
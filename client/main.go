@@ -1,65 +1,98 @@
 package main
 
 import (
+	"awsomeProject/albumclient"
+	"awsomeProject/config"
+	"awsomeProject/money"
 	"awsomeProject/pb"
+	"awsomeProject/tlsconfig"
+	"awsomeProject/tracing"
 	"context"
-	"io"
 	"log"
-	"time"
+	"log/slog"
+	"os"
 
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-var (
-	serverAddr = "localhost:50051"
-
-	timeoutDuration = 10 * time.Second
-	timeSleep       = 1 * time.Second
-)
-
 // Unary RPC
 // サーバーにtitleを送り、ファイルに存在するかの確認結果をAlbum型で受け取る関数
-func callGetAlbum(client pb.AlbumServiceClient, title string) {
-	// リクエストのタイムアウトを設定
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
-	defer cancel()
+func callGetAlbum(client *albumclient.Client, title string) {
+	album, err := client.GetAlbumByTitle(context.Background(), title)
+	if err != nil {
+		logError("client.GetAlbum", err)
+		return
+	}
+
+	log.Printf("response: %v", album)
+}
 
-	// GetAlbumメソッドを呼び出してサーバーにリクエストを送り、レスポンスを受け取る
-	resp, err := client.GetAlbum(ctx, &pb.GetAlbumRequest{Title: title})
+// Unary RPC
+// サーバーにidを送り、該当するAlbumを受け取る関数
+func callGetAlbumByID(client *albumclient.Client, id string) {
+	album, err := client.GetAlbum(context.Background(), id)
 	if err != nil {
-		log.Fatalf("client.GetAlbum failed: %v", err)
+		logError("client.GetAlbum", err)
+		return
 	}
 
-	log.Printf("response: %v", resp.Album)
+	log.Printf("response: %v", album)
 }
 
 // Server Streaming RPC
 // サーバーにartistを送り、ファイルに存在するAlbumをすべてAlbum型で受け取る関数
-func callListAlbums(client pb.AlbumServiceClient, artist string) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
-	defer cancel()
-
-	// ListAlbumsメソッドを呼び出してサーバーにリクエストを送り、ストリームを受け取る
-	stream, err := client.ListAlbums(ctx, &pb.ListAlbumsRequest{Artist: artist})
+func callListAlbums(client *albumclient.Client, artist string) {
+	err := client.ListAlbums(context.Background(), &pb.ListAlbumsRequest{Artist: artist}, func(album *pb.Album) error {
+		log.Printf("response: %v", album)
+		return nil
+	})
 	if err != nil {
-		log.Fatalf("client.ListAlbums failed: %v", err)
+		logError("client.ListAlbums", err)
 	}
+}
 
+// Unary RPC
+// サーバーに絞り込み条件を送り、条件に一致するAlbumを1ページずつすべて受け取る関数
+func callSearchAlbums(client *albumclient.Client, filter *pb.AlbumFilter, orderBy string) {
+	req := &pb.SearchAlbumsRequest{Filter: filter, OrderBy: orderBy, PageSize: 5}
 	for {
-		resp, err := stream.Recv()
+		resp, err := client.SearchAlbums(context.Background(), req)
 		if err != nil {
-			log.Printf("stream.Recv failed: %v", err)
-			break
+			logError("client.SearchAlbums", err)
+			return
+		}
+
+		log.Printf("page: %d albums (total: %d)", len(resp.Albums), resp.TotalSize)
+		for _, album := range resp.Albums {
+			log.Printf("response: %s / %s / %s", album.Title, album.Artist, money.Format(album.Price))
+		}
+
+		// next_page_tokenが空なら最後のページ
+		if resp.NextPageToken == "" {
+			return
 		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+// Unary RPC
+// サーバーにキーワードを送り、全文検索の結果を関連度の高い順に受け取る関数
+func callSearchAlbumsByQuery(client *albumclient.Client, query string) {
+	resp, err := client.SearchAlbums(context.Background(), &pb.SearchAlbumsRequest{Query: query, PageSize: 10})
+	if err != nil {
+		logError("client.SearchAlbums", err)
+		return
+	}
 
-		log.Printf("response: %v", resp.Album)
+	for _, result := range resp.Results {
+		log.Printf("response: %.3f %s / %s %v", result.Score, result.Album.Title, result.Album.Artist, result.MatchedTerms)
 	}
 }
 
 // Client Streaming RPC
 // サーバーに複数のtitleを送り、ファイルに存在するAlbumの総数・合計金額・メッセージを受け取る関数
-func callGetTotalAmount(client pb.AlbumServiceClient) {
+func callGetTotalAmount(client *albumclient.Client) {
 	titles := []string{
 		"Blue Train",
 		"Giant Steps",
@@ -68,105 +101,147 @@ func callGetTotalAmount(client pb.AlbumServiceClient) {
 		"A Portrait in Jazz",
 		"Chet Baker Sings",
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
-	defer cancel()
-
-	// GetTotalAmountメソッドを呼び出してクライアントストリームを作成
-	stream, err := client.GetTotalAmount(ctx)
-	if err != nil {
-		log.Fatalf("client.GetTotalAmount failed: %v", err)
-	}
-
-	// 複数のリクエストをストリームに送信
-	for _, title := range titles {
-		if err := stream.Send(&pb.GetTotalAmountRequest{Title: title}); err != nil {
-			log.Fatalf("client.GetTotalAmount: stream.Send(%s) failed: %v", title, err)
-		}
-
-		time.Sleep(timeSleep)
-	}
 
-	// サーバーからのレスポンスを受け取る
-	resp, err := stream.CloseAndRecv()
+	resp, err := client.GetTotalAmount(context.Background(), titles)
 	if err != nil {
-		log.Fatalf("client.GetTotalAmount: stream.CloseAndRecv failed: %v", err)
+		logError("client.GetTotalAmount", err)
+		return
 	}
 
 	log.Printf("response: %v", resp)
+	for _, total := range resp.Totals {
+		log.Printf("total: %s", money.Format(total))
+	}
 }
 
 // Bidirectional Streaming RPC
 // サーバーに複数のAlbumを連続で送信し、そのたびにサーバーからのメッセージを受け取る関数
-func callUploadAndNotify(client pb.AlbumServiceClient) {
+func callUploadAndNotify(client *albumclient.Client) {
 	// アルバムのデータを作成
 	albums := []*pb.Album{
-		{Title: "New Album", Artist: "New Artist", Price: 10.99},
-		{Title: "New Album 2", Artist: "New Artist 2", Price: 20.99},
-		{Title: "Blue Train", Artist: "John Coltrane", Price: 56.99},
-		{Title: "Jeru", Artist: "Gerry Mulligan", Price: 17.99},
+		{Title: "New Album", Artist: "New Artist", Price: money.MustParse("USD", "10.99")},
+		{Title: "New Album 2", Artist: "New Artist 2", Price: money.MustParse("USD", "20.99")},
+		{Title: "Blue Train", Artist: "John Coltrane", Price: money.MustParse("USD", "56.99")},
+		{Title: "Jeru", Artist: "Gerry Mulligan", Price: money.MustParse("USD", "17.99")},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutDuration)
-	defer cancel()
+	err := client.UploadAlbums(context.Background(), albums, func(resp *pb.UploadAndNotifyResponse) {
+		log.Printf("response: %v", resp)
+	})
+	if err != nil {
+		logError("client.UploadAndNotify", err)
+	}
+}
 
-	// UploadAndNotifyメソッドを呼び出して双方向ストリームを作成
-	stream, err := client.UploadAndNotify(ctx)
+// Unary RPC
+// サーバーにAlbumを送り、IDが採番されたAlbumを受け取る関数（失敗した場合はnilを返す）
+func callCreateAlbum(client *albumclient.Client, album *pb.Album) *pb.Album {
+	album, err := client.CreateAlbum(context.Background(), album)
 	if err != nil {
-		log.Fatalf("client.UploadAndNotify failed: %v", err)
+		logError("client.CreateAlbum", err)
+		return nil
 	}
 
-	waitc := make(chan struct{})
+	log.Printf("response: %v", album)
+	return album
+}
 
-	go func() {
-		for {
-			resp, err := stream.Recv()
-			if err == io.EOF {
-				close(waitc)
-				return
-			}
+// Unary RPC
+// サーバーにidを設定したAlbumと更新するフィールドを送り、更新後のAlbumを受け取る関数
+func callUpdateAlbum(client *albumclient.Client, album *pb.Album, paths ...string) {
+	// pathsで指定したフィールドだけを更新する
+	album, err := client.UpdateAlbum(context.Background(), album, paths...)
+	if err != nil {
+		logError("client.UpdateAlbum", err)
+		return
+	}
 
-			if err != nil {
-				log.Fatalf("client.UploadAndNotify: stream.Recv failed: %v", err)
-			}
+	log.Printf("response: %v", album)
+}
 
-			log.Printf("response: %v", resp)
-		}
-	}()
+// Unary RPC
+// サーバーにidを送り、該当するAlbumを削除する関数
+func callDeleteAlbum(client *albumclient.Client, id string) {
+	if err := client.DeleteAlbum(context.Background(), id); err != nil {
+		logError("client.DeleteAlbum", err)
+		return
+	}
 
-	// 複数のリクエストをストリームに送信
-	for _, album := range albums {
-		req := &pb.UploadAndNotifyRequest{Album: album}
-		if err := stream.Send(req); err != nil {
-			log.Fatalf("client.UploadAndNotify: stream.Send(%v) failed: %v", album, err)
-		}
+	log.Printf("deleted: %s", id)
+}
 
-		time.Sleep(timeSleep)
+// 設定に従って接続に使う認証情報を作成する関数
+func transportCredentials(cfg config.ClientTLS) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
 	}
 
-	// ストリームを閉じる
-	if err := stream.CloseSend(); err != nil {
-		log.Fatalf("client.UploadAndNotify: stream.CloseSend() failed: %v", err)
+	tlsConfig, err := tlsconfig.Client(cfg.CAFile, cfg.CertFile, cfg.KeyFile, cfg.ServerName)
+	if err != nil {
+		return nil, err
 	}
-	<-waitc
 
+	return credentials.NewTLS(tlsConfig), nil
 }
 
 func main() {
-	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// 設定の誤りは起動時にまとめて報告する
+	cfg, err := config.LoadClient(os.Args[0], os.Args[1:])
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	creds, err := transportCredentials(cfg.TLS)
+	if err != nil {
+		log.Fatalf("failed to load TLS config: %v", err)
+	}
 
+	// トレースの出力先を設定する（noneの場合はスパンを記録しない）
+	exporter, err := tracing.NewExporter(context.Background(), tracing.Options{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("failed to create trace exporter: %v", err)
+	}
+	tracerProvider := tracing.NewTracerProvider(exporter, "album-client", cfg.Tracing.SampleRatio)
+	defer tracerProvider.Shutdown(context.Background()) // 残りのスパンを書き出す
+
+	if cfg.Token != "" && !cfg.TLS.Enabled {
+		log.Println("warning: sending the token over an insecure connection")
+	}
+
+	client, err := albumclient.New(cfg.ServerAddr, albumclient.Options{
+		Timeout:              cfg.Timeout,
+		TransportCredentials: creds,
+		Token:                cfg.Token,
+		InsecureToken:        !cfg.TLS.Enabled,
+		SendInterval:         cfg.SendInterval,
+		Logger:               slog.Default(),
+		Tracing:              cfg.Tracing.Exporter != tracing.ExporterNone,
+	})
 	if err != nil {
 		log.Fatalf("fail to dial: %v", err)
 	}
-	defer conn.Close()
-	client := pb.NewAlbumServiceClient(conn)
+	defer client.Close()
 
 	// callGetAlbum(client, "Blue Train")
 	// callGetAlbum(client, "Not Exist Title")
 
 	// callListAlbums(client, "Miles Davis")
 
+	// callSearchAlbumsByQuery(client, "coltrane love")
+	// callSearchAlbums(client, &pb.AlbumFilter{Artist: "davis", MatchMode: pb.MatchMode_MATCH_MODE_CONTAINS, IgnoreCase: true}, "price desc")
+
 	// callGetTotalAmount(client)
 
+	// album := callCreateAlbum(client, &pb.Album{Title: "Somethin' Else", Artist: "Cannonball Adderley", Price: money.MustParse("USD", "24.99"), ReleaseYear: 1958, Genre: "Hard Bop"})
+	// callGetAlbumByID(client, album.Id)
+	// callUpdateAlbum(client, &pb.Album{Id: album.Id, Price: money.MustParse("USD", "19.99")}, "price")
+	// callDeleteAlbum(client, album.Id)
+
 	// Bidirectional Streaming RPCを実行
 	callUploadAndNotify(client)
 }
\ No newline at end of file
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// サーバーから返されたエラーのステータスコードと詳細情報をログ出力する関数
+func logError(method string, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		log.Printf("%s failed: %v", method, err)
+		return
+	}
+
+	log.Printf("%s failed: code=%s message=%q", method, st.Code(), st.Message())
+
+	// サーバーが付与した詳細情報を種類ごとに出力
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				log.Printf("  bad request: field=%s description=%q", v.GetField(), v.GetDescription())
+			}
+		case *errdetails.ResourceInfo:
+			log.Printf("  resource: type=%s name=%q description=%q", d.GetResourceType(), d.GetResourceName(), d.GetDescription())
+		case *errdetails.RetryInfo:
+			log.Printf("  retry after: %s", d.GetRetryDelay().AsDuration())
+		case error:
+			// 詳細情報のデコードに失敗した場合はerrorが入る
+			log.Printf("  failed to decode detail: %v", d)
+		default:
+			log.Printf("  detail: %v", d)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// クライアントの設定
+type Client struct {
+	ServerAddr   string        `yaml:"server_addr" env:"ALBUM_SERVER_ADDR" flag:"server-addr" usage:"address of the album server"`
+	Timeout      time.Duration `yaml:"timeout" env:"ALBUM_TIMEOUT" flag:"timeout" usage:"deadline of each RPC"`
+	SendInterval time.Duration `yaml:"send_interval" env:"ALBUM_SEND_INTERVAL" flag:"send-interval" usage:"interval between messages sent on client streams"`
+	Token        string        `yaml:"token" env:"ALBUM_TOKEN" flag:"token" usage:"bearer token (api key or JWT) sent with every RPC"`
+
+	TLS     ClientTLS `yaml:"tls"`
+	Tracing Tracing   `yaml:"tracing"`
+}
+
+// クライアントのTLSの設定
+type ClientTLS struct {
+	Enabled    bool   `yaml:"enabled" env:"ALBUM_TLS" flag:"tls" usage:"connect over TLS"`
+	CAFile     string `yaml:"ca_file" env:"ALBUM_TLS_CA" flag:"tls-ca" usage:"CA certificate used to verify the server (PEM, system roots if empty)"`
+	ServerName string `yaml:"server_name" env:"ALBUM_TLS_SERVER_NAME" flag:"tls-server-name" usage:"server name used to verify the certificate"`
+	CertFile   string `yaml:"cert_file" env:"ALBUM_TLS_CERT" flag:"tls-cert" usage:"client certificate file for mutual TLS (PEM)"`
+	KeyFile    string `yaml:"key_file" env:"ALBUM_TLS_KEY" flag:"tls-key" usage:"client private key file for mutual TLS (PEM)"`
+}
+
+// クライアントの設定のデフォルト値を返す関数
+func DefaultClient() *Client {
+	return &Client{
+		ServerAddr:   "localhost:50051",
+		Timeout:      10 * time.Second,
+		SendInterval: 1 * time.Second,
+		Tracing:      defaultTracing(),
+	}
+}
+
+// デフォルト値、設定ファイル、環境変数、フラグの順にクライアントの設定を読み込み、内容を確認する関数
+// 設定ファイルは-configフラグか環境変数ALBUM_CLIENT_CONFIGで指定する
+func LoadClient(name string, args []string) (*Client, error) {
+	cfg := DefaultClient()
+	if err := load(cfg, name, "ALBUM_CLIENT_CONFIG", args); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// 設定の内容を確認する関数
+// 誤りはまとめて返す
+func (c *Client) Validate() error {
+	var errs []error
+
+	if c.ServerAddr == "" {
+		errs = append(errs, errors.New("server_addr must be set"))
+	}
+	if c.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout must be positive"))
+	}
+	if c.SendInterval < 0 {
+		errs = append(errs, errors.New("send_interval must not be negative"))
+	}
+	if c.TLS.Enabled {
+		if c.TLS.CAFile != "" {
+			if err := checkFile("tls.ca_file", c.TLS.CAFile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		// クライアント証明書は証明書と秘密鍵の両方を指定する
+		if c.TLS.CertFile != "" || c.TLS.KeyFile != "" {
+			if err := checkFile("tls.cert_file", c.TLS.CertFile); err != nil {
+				errs = append(errs, err)
+			}
+			if err := checkFile("tls.key_file", c.TLS.KeyFile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	errs = append(errs, c.Tracing.validate()...)
+
+	return errors.Join(errs...)
+}
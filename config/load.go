@@ -0,0 +1,214 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 設定項目を表す構造体のフィールド
+// タグで設定ファイルのキー（yaml）、環境変数名（env）、フラグ名（flag）と説明（usage）を指定する
+type field struct {
+	value reflect.Value
+	env   string
+	flag  string
+	usage string
+}
+
+// 設定をデフォルト値、設定ファイル、環境変数、フラグの順に読み込む関数
+// 後から読み込んだものほど優先される
+//
+// 設定ファイルのパスは-configフラグか、configEnvで指定した環境変数で指定する
+func load(cfg any, name, configEnv string, args []string) error {
+	fields := collectFields(reflect.ValueOf(cfg).Elem())
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(configEnv), "path to a YAML configuration file (env: "+configEnv+")")
+
+	// フラグの値は環境変数より優先するため、ここでは記録だけしておき最後に反映する
+	var flagValues []func() error
+	for _, f := range fields {
+		if f.flag == "" {
+			continue
+		}
+		usage := f.usage
+		if f.env != "" {
+			usage += " (env: " + f.env + ")"
+		}
+		fs.Var(&flagValue{field: f, defaultValue: fmt.Sprint(f.value.Interface()), set: &flagValues}, f.flag, usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *configPath != "" {
+		if err := loadFile(cfg, *configPath); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if s, ok := os.LookupEnv(f.env); ok {
+			if err := setValue(f.value, s); err != nil {
+				return fmt.Errorf("environment variable %s: %w", f.env, err)
+			}
+		}
+	}
+
+	for _, apply := range flagValues {
+		if err := apply(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// フラグで指定された値を記録するflag.Value
+type flagValue struct {
+	field        field
+	defaultValue string
+	set          *[]func() error
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.defaultValue
+}
+
+func (v *flagValue) Set(s string) error {
+	// 値の形式の誤りはフラグの解析時に報告する
+	if err := setValue(reflect.New(v.field.value.Type()).Elem(), s); err != nil {
+		return err
+	}
+	*v.set = append(*v.set, func() error { return setValue(v.field.value, s) })
+	return nil
+}
+
+// bool型のフィールドは値を省略して指定できるようにする
+func (v *flagValue) IsBoolFlag() bool {
+	return v.field.value.Kind() == reflect.Bool
+}
+
+// YAMLの設定ファイルを読み込む関数
+// 綴りの誤りに気付けるよう、存在しないキーはエラーにする
+func loadFile(cfg any, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// 構造体を再帰的にたどり、設定項目となるフィールドを集める関数
+func collectFields(v reflect.Value) []field {
+	var fields []field
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		fv := v.Field(i)
+
+		if fv.Kind() == reflect.Struct && fv.Type() != reflect.TypeOf(time.Duration(0)) {
+			fields = append(fields, collectFields(fv)...)
+			continue
+		}
+
+		fields = append(fields, field{
+			value: fv,
+			env:   sf.Tag.Get("env"),
+			flag:  sf.Tag.Get("flag"),
+			usage: sf.Tag.Get("usage"),
+		})
+	}
+
+	return fields
+}
+
+// 文字列をフィールドの型に変換して設定する関数
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type: %s", v.Type())
+		}
+		// カンマ区切りの文字列をスライスにする
+		var values []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		v.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported config type: %s", v.Type())
+	}
+
+	return nil
+}
+
+// ファイルが存在し読み取れるか確認する関数
+func checkFile(name, path string) error {
+	if path == "" {
+		return fmt.Errorf("%s must be set", name)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 設定ファイルを書き出し、そのパスを返す関数
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// 同じ項目をデフォルト値・設定ファイル・環境変数・フラグのそれぞれで指定し、優先される値を確認する
+func TestLoadServerPrecedence(t *testing.T) {
+	keys := []struct {
+		name  string
+		yaml  func(value string) string // 設定ファイルでの書き方
+		env   string
+		flag  string
+		layer [3]string // 設定ファイル、環境変数、フラグで指定する値
+		def   string
+		get   func(*Server) string
+	}{
+		{
+			name:  "listen_addr",
+			yaml:  func(v string) string { return "listen_addr: " + v },
+			env:   "ALBUM_LISTEN_ADDR",
+			flag:  "listen-addr",
+			layer: [3]string{":6001", ":6002", ":6003"},
+			def:   ":50051",
+			get:   func(c *Server) string { return c.ListenAddr },
+		},
+		{
+			name:  "stream_interval",
+			yaml:  func(v string) string { return "stream_interval: " + v },
+			env:   "ALBUM_STREAM_INTERVAL",
+			flag:  "stream-interval",
+			layer: [3]string{"1s", "2s", "3s"},
+			def:   "0s",
+			get:   func(c *Server) string { return c.StreamInterval.String() },
+		},
+		{
+			name:  "limits.max_concurrent_streams",
+			yaml:  func(v string) string { return "limits:\n  max_concurrent_streams: " + v },
+			env:   "ALBUM_MAX_CONCURRENT_STREAMS",
+			flag:  "max-concurrent-streams",
+			layer: [3]string{"10", "20", "30"},
+			def:   "0",
+			get:   func(c *Server) string { return fmt.Sprint(c.Limits.MaxConcurrentStreams) },
+		},
+		{
+			name:  "storage.backend",
+			yaml:  func(v string) string { return "storage:\n  backend: " + v },
+			env:   "ALBUM_STORAGE",
+			flag:  "storage",
+			layer: [3]string{"sqlite", "json", "sqlite"},
+			def:   "json",
+			get:   func(c *Server) string { return c.Storage.Backend },
+		},
+	}
+
+	for _, key := range keys {
+		// 設定ファイル・環境変数・フラグのそれぞれで指定するかどうかのすべての組み合わせ
+		for mask := range 8 {
+			file, env, flag := mask&1 != 0, mask&2 != 0, mask&4 != 0
+			t.Run(fmt.Sprintf("%s/file=%v,env=%v,flag=%v", key.name, file, env, flag), func(t *testing.T) {
+				t.Setenv("ALBUM_SERVER_CONFIG", "")
+				want := key.def
+				var args []string
+				if file {
+					args = append(args, "-config", writeConfigFile(t, key.yaml(key.layer[0])))
+					want = key.layer[0]
+				}
+				if env {
+					t.Setenv(key.env, key.layer[1])
+					want = key.layer[1]
+				}
+				if flag {
+					args = append(args, "-"+key.flag, key.layer[2])
+					want = key.layer[2]
+				}
+
+				cfg, err := LoadServer("server", args)
+				if err != nil {
+					t.Fatalf("LoadServer() error = %v", err)
+				}
+				if got := key.get(cfg); got != want {
+					t.Errorf("%s = %q, want %q", key.name, got, want)
+				}
+			})
+		}
+	}
+}
+
+// 設定ファイルのパスは環境変数でも指定できる
+func TestLoadServerConfigFromEnv(t *testing.T) {
+	t.Setenv("ALBUM_SERVER_CONFIG", writeConfigFile(t, "listen_addr: \":6001\""))
+
+	cfg, err := LoadServer("server", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ListenAddr != ":6001" {
+		t.Errorf("listen_addr = %q, want %q", cfg.ListenAddr, ":6001")
+	}
+}
+
+func TestLoadServerInvalidEnv(t *testing.T) {
+	tests := []struct {
+		env   string
+		value string
+	}{
+		{env: "ALBUM_STREAM_INTERVAL", value: "soon"},
+		{env: "ALBUM_LOGGING", value: "maybe"},
+		{env: "ALBUM_MAX_CONCURRENT_STREAMS", value: "-1"},
+		{env: "ALBUM_MAX_RECV_MSG_SIZE", value: "4MB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("ALBUM_SERVER_CONFIG", "")
+			t.Setenv(tt.env, tt.value)
+
+			_, err := LoadServer("server", nil)
+			if err == nil {
+				t.Fatalf("LoadServer() error = nil, want error for %s=%s", tt.env, tt.value)
+			}
+			// どの環境変数が誤っているか分かる
+			if !strings.Contains(err.Error(), tt.env) {
+				t.Errorf("error %q does not name %s", err, tt.env)
+			}
+		})
+	}
+}
+
+func TestLoadServerInvalidFlag(t *testing.T) {
+	t.Setenv("ALBUM_SERVER_CONFIG", "")
+
+	if _, err := LoadServer("server", []string{"-stream-interval", "soon"}); err == nil {
+		t.Error("LoadServer() error = nil, want error for an invalid flag value")
+	}
+}
+
+// 綴りを誤ったキーは無視せずエラーにする
+func TestLoadServerUnknownFileKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+	}{
+		{name: "top level", content: "listen_adr: \":6001\"", key: "listen_adr"},
+		{name: "nested", content: "storage:\n  backend_type: sqlite", key: "backend_type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ALBUM_SERVER_CONFIG", "")
+
+			_, err := LoadServer("server", []string{"-config", writeConfigFile(t, tt.content)})
+			if err == nil {
+				t.Fatal("LoadServer() error = nil, want error for an unknown key")
+			}
+			if !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("error %q does not name %s", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestLoadClientPrecedence(t *testing.T) {
+	t.Setenv("ALBUM_CLIENT_CONFIG", "")
+	t.Setenv("ALBUM_TIMEOUT", "20s")
+	path := writeConfigFile(t, "server_addr: file:50051\ntimeout: 5s\nsend_interval: 2s")
+
+	cfg, err := LoadClient("client", []string{"-config", path, "-send-interval", "0s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ServerAddr != "file:50051" || cfg.Timeout.String() != "20s" || cfg.SendInterval != 0 {
+		t.Errorf("LoadClient() = %+v, want server_addr from the file, timeout from env and send_interval from flags", cfg)
+	}
+}
@@ -0,0 +1,321 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// サーバーの設定
+type Server struct {
+	ListenAddr      string        `yaml:"listen_addr" env:"ALBUM_LISTEN_ADDR" flag:"listen-addr" usage:"address the server listens on"`
+	StreamInterval  time.Duration `yaml:"stream_interval" env:"ALBUM_STREAM_INTERVAL" flag:"stream-interval" usage:"interval between messages sent by ListAlbums (for demos, 0 disables)"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"ALBUM_SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"time allowed for in-flight RPCs to finish on shutdown before connections are closed"`
+	Reflection      bool          `yaml:"reflection" env:"ALBUM_REFLECTION" flag:"reflection" usage:"register the gRPC server reflection service (for grpcurl and other debugging tools)"`
+
+	Log          Log          `yaml:"log"`
+	Storage      Storage      `yaml:"storage"`
+	TLS          ServerTLS    `yaml:"tls"`
+	Interceptors Interceptors `yaml:"interceptors"`
+	Limits       Limits       `yaml:"limits"`
+	Health       Health       `yaml:"health"`
+	Auth         Auth         `yaml:"auth"`
+	Metrics      Metrics      `yaml:"metrics"`
+	Tracing      Tracing      `yaml:"tracing"`
+	RateLimit    RateLimit    `yaml:"rate_limit"`
+}
+
+// ログの設定
+type Log struct {
+	Level  string `yaml:"level" env:"ALBUM_LOG_LEVEL" flag:"log-level" usage:"minimum log level (debug, info, warn or error)"`
+	Format string `yaml:"format" env:"ALBUM_LOG_FORMAT" flag:"log-format" usage:"log output format (text or json)"`
+}
+
+// ログレベルを返すメソッド
+// Validateで確認済みのため、不正な値の場合はInfoを返す
+func (l Log) SlogLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return slog.LevelInfo
+	}
+
+	return level
+}
+
+// アルバムデータの保存先の設定
+type Storage struct {
+	Backend    string `yaml:"backend" env:"ALBUM_STORAGE" flag:"storage" usage:"album storage backend (json or sqlite)"`
+	JSONPath   string `yaml:"json_path" env:"ALBUM_JSON_PATH" flag:"json-path" usage:"path to the JSON file used by the json backend"`
+	SQLitePath string `yaml:"sqlite_path" env:"ALBUM_SQLITE_PATH" flag:"sqlite-path" usage:"path to the SQLite database used by the sqlite backend"`
+	ImportPath string `yaml:"import_path" env:"ALBUM_IMPORT" flag:"import" usage:"JSON file to import into the sqlite backend on start"`
+}
+
+// サーバーのTLSの設定
+// 証明書と秘密鍵のファイルは、更新されると再起動せずに読み込み直す
+type ServerTLS struct {
+	Enabled      bool   `yaml:"enabled" env:"ALBUM_TLS" flag:"tls" usage:"serve over TLS"`
+	CertFile     string `yaml:"cert_file" env:"ALBUM_TLS_CERT" flag:"tls-cert" usage:"server certificate file (PEM)"`
+	KeyFile      string `yaml:"key_file" env:"ALBUM_TLS_KEY" flag:"tls-key" usage:"server private key file (PEM)"`
+	ClientCAFile string `yaml:"client_ca_file" env:"ALBUM_TLS_CLIENT_CA" flag:"tls-client-ca" usage:"CA certificate used to verify client certificates (PEM, enables mutual TLS)"`
+}
+
+// インターセプターの有効・無効の設定
+type Interceptors struct {
+	Logging bool `yaml:"logging" env:"ALBUM_LOGGING" flag:"logging" usage:"log every RPC with its peer, status code, duration and stream message counts"`
+
+	// インターセプターの名前（InterceptorNames）ごとに、実行するメソッドを絞り込む条件（設定ファイルでのみ指定できる）
+	Methods map[string]MethodFilter `yaml:"methods"`
+}
+
+// interceptors.methodsに指定できるインターセプターの名前
+// server/interceptorのチェーンでの実行順と同じ順に並べる
+var InterceptorNames = []string{"request_id", "tracing", "logging", "recovery", "metrics", "auth", "rate_limit", "authz", "validate"}
+
+// インターセプターを実行するメソッドの条件
+// メソッドは"/album.AlbumService/GetAlbum"のようなフルメソッド名か、"/"で終わるサービス名で指定する
+type MethodFilter struct {
+	Include []string `yaml:"include"` // 空でなければ、一致するメソッドでだけ実行する
+	Exclude []string `yaml:"exclude"` // 一致するメソッドでは実行しない（includeより優先する）
+}
+
+// gRPCサーバーの制限の設定（0は制限なし、またはgRPCのデフォルト値を使う）
+type Limits struct {
+	MaxRecvMsgSize       int    `yaml:"max_recv_msg_size" env:"ALBUM_MAX_RECV_MSG_SIZE" flag:"max-recv-msg-size" usage:"maximum size in bytes of a received message"`
+	MaxSendMsgSize       int    `yaml:"max_send_msg_size" env:"ALBUM_MAX_SEND_MSG_SIZE" flag:"max-send-msg-size" usage:"maximum size in bytes of a sent message"`
+	MaxConcurrentStreams uint32 `yaml:"max_concurrent_streams" env:"ALBUM_MAX_CONCURRENT_STREAMS" flag:"max-concurrent-streams" usage:"maximum number of concurrent streams per connection"`
+}
+
+// ヘルスチェックの設定
+type Health struct {
+	CheckInterval time.Duration `yaml:"check_interval" env:"ALBUM_HEALTH_CHECK_INTERVAL" flag:"health-check-interval" usage:"interval between checks that the album store is readable and writable"`
+	CheckTimeout  time.Duration `yaml:"check_timeout" env:"ALBUM_HEALTH_CHECK_TIMEOUT" flag:"health-check-timeout" usage:"deadline of each album store check"`
+}
+
+// Bearerトークンによる認証の設定
+// APIキーとJWTの鍵は、設定ファイルに直接書かずに別のファイルから読み込む
+type Auth struct {
+	Enabled       bool     `yaml:"enabled" env:"ALBUM_AUTH" flag:"auth" usage:"require a bearer token (api key or JWT) on every RPC"`
+	APIKeysFile   string   `yaml:"api_keys_file" env:"ALBUM_API_KEYS_FILE" flag:"api-keys-file" usage:"YAML file listing static api keys with their subject and roles"`
+	JWTSecretFile string   `yaml:"jwt_secret_file" env:"ALBUM_JWT_SECRET_FILE" flag:"jwt-secret-file" usage:"file containing the HMAC secret used to verify JWTs"`
+	JWTIssuer     string   `yaml:"jwt_issuer" env:"ALBUM_JWT_ISSUER" flag:"jwt-issuer" usage:"required iss claim of JWTs (not checked if empty)"`
+	JWTAudience   string   `yaml:"jwt_audience" env:"ALBUM_JWT_AUDIENCE" flag:"jwt-audience" usage:"required aud claim of JWTs (not checked if empty)"`
+	PublicMethods []string `yaml:"public_methods" env:"ALBUM_AUTH_PUBLIC_METHODS" flag:"auth-public-methods" usage:"comma-separated methods that do not require a token (entries ending with / match a whole service)"`
+	PolicyFile    string   `yaml:"policy_file" env:"ALBUM_AUTH_POLICY_FILE" flag:"auth-policy-file" usage:"YAML file mapping methods to the roles allowed to call them (any authenticated caller is allowed if empty)"`
+}
+
+// 呼び出し元ごとの頻度制限の設定
+// 呼び出し元は、認証されていれば認証された名前、そうでなければ接続元のIPアドレスで区別する
+type RateLimit struct {
+	Enabled                 bool    `yaml:"enabled" env:"ALBUM_RATE_LIMIT" flag:"rate-limit" usage:"limit the request rate of each caller"`
+	UnaryPerSecond          float64 `yaml:"unary_per_second" env:"ALBUM_RATE_LIMIT_UNARY" flag:"rate-limit-unary" usage:"unary calls per second allowed for each caller (0 for unlimited)"`
+	UnaryBurst              int     `yaml:"unary_burst" env:"ALBUM_RATE_LIMIT_UNARY_BURST" flag:"rate-limit-unary-burst" usage:"unary calls allowed in a burst"`
+	StreamMessagesPerSecond float64 `yaml:"stream_messages_per_second" env:"ALBUM_RATE_LIMIT_STREAM" flag:"rate-limit-stream" usage:"streams opened plus messages received on streams per second allowed for each caller (0 for unlimited)"`
+	StreamBurst             int     `yaml:"stream_burst" env:"ALBUM_RATE_LIMIT_STREAM_BURST" flag:"rate-limit-stream-burst" usage:"streams opened plus stream messages allowed in a burst"`
+}
+
+// Prometheusのメトリクスの設定
+type Metrics struct {
+	Enabled    bool   `yaml:"enabled" env:"ALBUM_METRICS" flag:"metrics" usage:"expose Prometheus metrics over HTTP"`
+	ListenAddr string `yaml:"listen_addr" env:"ALBUM_METRICS_ADDR" flag:"metrics-addr" usage:"address of the HTTP server exposing metrics"`
+	Path       string `yaml:"path" env:"ALBUM_METRICS_PATH" flag:"metrics-path" usage:"HTTP path of the metrics endpoint"`
+}
+
+// サーバーの設定のデフォルト値を返す関数
+func DefaultServer() *Server {
+	return &Server{
+		ListenAddr:      ":50051",
+		ShutdownTimeout: 10 * time.Second,
+		Log: Log{
+			Level:  "info",
+			Format: "text",
+		},
+		Storage: Storage{
+			Backend:    "json",
+			JSONPath:   "db/album.json",
+			SQLitePath: "db/album.db",
+		},
+		Interceptors: Interceptors{
+			Logging: true,
+		},
+		Health: Health{
+			CheckInterval: 10 * time.Second,
+			CheckTimeout:  5 * time.Second,
+		},
+		Tracing: defaultTracing(),
+		RateLimit: RateLimit{
+			UnaryPerSecond:          50,
+			UnaryBurst:              100,
+			StreamMessagesPerSecond: 100,
+			StreamBurst:             200,
+		},
+		Metrics: Metrics{
+			ListenAddr: ":9090",
+			Path:       "/metrics",
+		},
+		Auth: Auth{
+			// ヘルスチェックとリフレクションはトークンなしで使えるようにする
+			PublicMethods: []string{
+				"/grpc.health.v1.Health/",
+				"/grpc.reflection.v1.ServerReflection/",
+				"/grpc.reflection.v1alpha.ServerReflection/",
+			},
+		},
+	}
+}
+
+// デフォルト値、設定ファイル、環境変数、フラグの順にサーバーの設定を読み込み、内容を確認する関数
+// 設定ファイルは-configフラグか環境変数ALBUM_SERVER_CONFIGで指定する
+func LoadServer(name string, args []string) (*Server, error) {
+	cfg := DefaultServer()
+	if err := load(cfg, name, "ALBUM_SERVER_CONFIG", args); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// 設定の内容を確認する関数
+// 誤りはまとめて返す
+func (c *Server) Validate() error {
+	var errs []error
+
+	if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+		errs = append(errs, fmt.Errorf("listen_addr: %w", err))
+	}
+	if c.StreamInterval < 0 {
+		errs = append(errs, errors.New("stream_interval must not be negative"))
+	}
+	// 0では停止時にすぐ強制停止となり、実行中のRPCを待てないため認めない
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown_timeout must be positive"))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		errs = append(errs, fmt.Errorf("log.format: unknown format %q (text or json)", c.Log.Format))
+	}
+
+	switch c.Storage.Backend {
+	case "json":
+		if c.Storage.JSONPath == "" {
+			errs = append(errs, errors.New("storage.json_path must be set for the json backend"))
+		}
+		if c.Storage.ImportPath != "" {
+			errs = append(errs, errors.New("storage.import_path is only supported by the sqlite backend"))
+		}
+	case "sqlite":
+		if c.Storage.SQLitePath == "" {
+			errs = append(errs, errors.New("storage.sqlite_path must be set for the sqlite backend"))
+		}
+		if c.Storage.ImportPath != "" {
+			if err := checkFile("storage.import_path", c.Storage.ImportPath); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend: unknown backend %q (json or sqlite)", c.Storage.Backend))
+	}
+
+	if c.TLS.Enabled {
+		if err := checkFile("tls.cert_file", c.TLS.CertFile); err != nil {
+			errs = append(errs, err)
+		}
+		if err := checkFile("tls.key_file", c.TLS.KeyFile); err != nil {
+			errs = append(errs, err)
+		}
+		if c.TLS.ClientCAFile != "" {
+			if err := checkFile("tls.client_ca_file", c.TLS.ClientCAFile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	} else if c.TLS.ClientCAFile != "" {
+		errs = append(errs, errors.New("tls.client_ca_file requires tls.enabled"))
+	}
+
+	if c.Limits.MaxRecvMsgSize < 0 {
+		errs = append(errs, errors.New("limits.max_recv_msg_size must not be negative"))
+	}
+	if c.Limits.MaxSendMsgSize < 0 {
+		errs = append(errs, errors.New("limits.max_send_msg_size must not be negative"))
+	}
+
+	if c.Auth.Enabled {
+		if c.Auth.APIKeysFile == "" && c.Auth.JWTSecretFile == "" {
+			errs = append(errs, errors.New("auth requires auth.api_keys_file or auth.jwt_secret_file"))
+		}
+		if c.Auth.APIKeysFile != "" {
+			if err := checkFile("auth.api_keys_file", c.Auth.APIKeysFile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if c.Auth.JWTSecretFile != "" {
+			if err := checkFile("auth.jwt_secret_file", c.Auth.JWTSecretFile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if c.Auth.PolicyFile != "" {
+			if err := checkFile("auth.policy_file", c.Auth.PolicyFile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	} else if c.Auth.PolicyFile != "" {
+		errs = append(errs, errors.New("auth.policy_file requires auth.enabled"))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Interceptors.Methods)) {
+		if !slices.Contains(InterceptorNames, name) {
+			errs = append(errs, fmt.Errorf("interceptors.methods: unknown interceptor %q (%s)", name, strings.Join(InterceptorNames, ", ")))
+			continue
+		}
+		filter := c.Interceptors.Methods[name]
+		for _, method := range slices.Concat(filter.Include, filter.Exclude) {
+			if !validMethod(method) {
+				errs = append(errs, fmt.Errorf("interceptors.methods.%s: invalid method %q (expected /package.Service/Method or /package.Service/)", name, method))
+			}
+		}
+	}
+
+	if c.Metrics.Enabled {
+		if _, _, err := net.SplitHostPort(c.Metrics.ListenAddr); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.listen_addr: %w", err))
+		}
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			errs = append(errs, errors.New("metrics.path must start with /"))
+		}
+	}
+
+	errs = append(errs, c.Tracing.validate()...)
+
+	if c.RateLimit.UnaryPerSecond < 0 || c.RateLimit.StreamMessagesPerSecond < 0 {
+		errs = append(errs, errors.New("rate_limit rates must not be negative"))
+	}
+	if c.RateLimit.UnaryBurst < 0 || c.RateLimit.StreamBurst < 0 {
+		errs = append(errs, errors.New("rate_limit bursts must not be negative"))
+	}
+
+	if c.Health.CheckInterval <= 0 {
+		errs = append(errs, errors.New("health.check_interval must be positive"))
+	}
+	if c.Health.CheckTimeout <= 0 {
+		errs = append(errs, errors.New("health.check_timeout must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// "/package.Service/Method"または"/package.Service/"の形式か判定する関数
+func validMethod(method string) bool {
+	service, name, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	return strings.HasPrefix(method, "/") && ok && service != "" && !strings.Contains(name, "/")
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServerValidateShutdownTimeout(t *testing.T) {
+	cfg := DefaultServer()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+
+	// 0では停止時に実行中のRPCを待たずに強制停止してしまう
+	cfg.ShutdownTimeout = 0
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "shutdown_timeout") {
+		t.Errorf("Validate() error = %v, want shutdown_timeout error", err)
+	}
+}
+
+func TestServerValidateInterceptorMethods(t *testing.T) {
+	cfg := DefaultServer()
+	cfg.Interceptors.Methods = map[string]MethodFilter{
+		"logging":    {Exclude: []string{"/grpc.health.v1.Health/"}},
+		"rate_limit": {Include: []string{"/album.AlbumService/UploadAndNotify"}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// チェーンにないインターセプターの名前は誤記として扱う
+	cfg.Interceptors.Methods["loging"] = MethodFilter{Exclude: []string{"/grpc.health.v1.Health/"}}
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `unknown interceptor "loging"`) {
+		t.Errorf("Validate() error = %v, want unknown interceptor error", err)
+	}
+
+	delete(cfg.Interceptors.Methods, "loging")
+	cfg.Interceptors.Methods["logging"] = MethodFilter{Exclude: []string{"grpc.health.v1.Health"}}
+	err = cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "invalid method") {
+		t.Errorf("Validate() error = %v, want invalid method error", err)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OpenTelemetryのトレースの設定（サーバーとクライアントで共通）
+type Tracing struct {
+	Exporter     string  `yaml:"exporter" env:"ALBUM_TRACING_EXPORTER" flag:"tracing-exporter" usage:"where to export trace spans (none, stdout or otlp)"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" env:"ALBUM_TRACING_OTLP_ENDPOINT" flag:"tracing-otlp-endpoint" usage:"OTLP/gRPC collector address used by the otlp exporter"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" env:"ALBUM_TRACING_OTLP_INSECURE" flag:"tracing-otlp-insecure" usage:"connect to the OTLP collector without TLS"`
+	SampleRatio  float64 `yaml:"sample_ratio" env:"ALBUM_TRACING_SAMPLE_RATIO" flag:"tracing-sample-ratio" usage:"fraction of new traces to record (0 to 1)"`
+}
+
+// トレースの設定のデフォルト値（トレースを記録しない）
+func defaultTracing() Tracing {
+	return Tracing{
+		Exporter:     "none",
+		OTLPEndpoint: "localhost:4317",
+		SampleRatio:  1,
+	}
+}
+
+func (t Tracing) validate() []error {
+	var errs []error
+
+	switch t.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if t.OTLPEndpoint == "" {
+			errs = append(errs, errors.New("tracing.otlp_endpoint must be set for the otlp exporter"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: unknown exporter %q (none, stdout or otlp)", t.Exporter))
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		errs = append(errs, errors.New("tracing.sample_ratio must be between 0 and 1"))
+	}
+
+	return errs
+}
@@ -0,0 +1,225 @@
+package money
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+)
+
+const nanosPerUnit = 1_000_000_000
+
+var (
+	ErrInvalidValue     = errors.New("invalid money value")         // Moneyの値が不正
+	ErrCurrencyMismatch = errors.New("currency codes do not match") // 異なる通貨同士を計算しようとした
+	ErrOverflow         = errors.New("money value overflows")       // 計算結果がint64に収まらない
+)
+
+// "56.99"のような10進数の文字列からMoneyを作成する関数
+// floatを経由しないため、小数点以下9桁までは誤差なく表現できる
+func Parse(currencyCode, value string) (*moneypb.Money, error) {
+	s := strings.TrimSpace(value)
+	negative := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	// 符号は先頭に1つだけ許し、整数部と小数部は数字だけで構成されていなければならない
+	// （ParseIntは符号も受け付けるため、"--5"や"1.+5"のような値を事前に弾く）
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || len(fracPart) > 9 || !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidValue, value)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if negative {
+		// 絶対値ではint64に収まらない最小値も読めるよう、符号を付けたまま読む
+		intPart = "-" + intPart
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidValue, value)
+	}
+	var nanos int64
+	if fracPart != "" {
+		// 9桁になるよう右側を0で埋めてからナノ単位の整数として読む
+		nanos, err = strconv.ParseInt(fracPart+strings.Repeat("0", 9-len(fracPart)), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidValue, value)
+		}
+	}
+
+	m := &moneypb.Money{CurrencyCode: currencyCode, Units: units, Nanos: int32(nanos)}
+	if negative {
+		m.Nanos = -m.Nanos
+	}
+
+	return m, Validate(m)
+}
+
+// Parseと同じだが、不正な値の場合はpanicする関数
+// コード中に書いた固定値からMoneyを作るときに使う
+func MustParse(currencyCode, value string) *moneypb.Money {
+	m, err := Parse(currencyCode, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Moneyが仕様（google/type/money.proto）を満たしているか確認する関数
+func Validate(m *moneypb.Money) error {
+	if m == nil {
+		return fmt.Errorf("%w: nil", ErrInvalidValue)
+	}
+	if len(m.CurrencyCode) != 3 || strings.ToUpper(m.CurrencyCode) != m.CurrencyCode {
+		return fmt.Errorf("%w: currency code must be a 3-letter ISO 4217 code: %q", ErrInvalidValue, m.CurrencyCode)
+	}
+	if m.Nanos <= -nanosPerUnit || m.Nanos >= nanosPerUnit {
+		return fmt.Errorf("%w: nanos out of range: %d", ErrInvalidValue, m.Nanos)
+	}
+	// unitsとnanosの符号は一致していなければならない
+	if m.Units > 0 && m.Nanos < 0 || m.Units < 0 && m.Nanos > 0 {
+		return fmt.Errorf("%w: units and nanos have different signs", ErrInvalidValue)
+	}
+
+	return nil
+}
+
+// 同じ通貨の2つのMoneyを足し合わせる関数
+func Add(a, b *moneypb.Money) (*moneypb.Money, error) {
+	if a.CurrencyCode != b.CurrencyCode {
+		return nil, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.CurrencyCode, b.CurrencyCode)
+	}
+
+	units, err := addUnits(a.Units, b.Units)
+	if err != nil {
+		return nil, err
+	}
+	nanos := int64(a.Nanos) + int64(b.Nanos)
+
+	// nanosの桁あふれをunitsに繰り上げる（繰り上げでオーバーフローすることもある）
+	units, err = addUnits(units, nanos/nanosPerUnit)
+	if err != nil {
+		return nil, err
+	}
+	nanos %= nanosPerUnit
+
+	// unitsとnanosの符号をそろえる
+	switch {
+	case units > 0 && nanos < 0:
+		units--
+		nanos += nanosPerUnit
+	case units < 0 && nanos > 0:
+		units++
+		nanos -= nanosPerUnit
+	}
+
+	return &moneypb.Money{CurrencyCode: a.CurrencyCode, Units: units, Nanos: int32(nanos)}, nil
+}
+
+// 同じ通貨の2つのMoneyを比較する関数
+// a < bなら負、a == bなら0、a > bなら正の値を返す
+func Compare(a, b *moneypb.Money) (int, error) {
+	if a.CurrencyCode != b.CurrencyCode {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.CurrencyCode, b.CurrencyCode)
+	}
+
+	// unitsとnanosの符号は一致しているため、units、nanosの順に比べればよい
+	if c := cmp.Compare(a.Units, b.Units); c != 0 {
+		return c, nil
+	}
+
+	return cmp.Compare(a.Nanos, b.Nanos), nil
+}
+
+// オーバーフローを検出しながら2つのunitsを足し合わせる関数
+func addUnits(a, b int64) (int64, error) {
+	sum := a + b
+	// 符号が同じ値同士の和で符号が変わった場合はオーバーフロー
+	if (a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0) {
+		return 0, ErrOverflow
+	}
+
+	return sum, nil
+}
+
+// sが数字だけで構成されているか判定する関数（空文字列はtrue）
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Moneyを"56.99 USD"の形式の文字列にする関数
+func Format(m *moneypb.Money) string {
+	if m == nil {
+		return ""
+	}
+
+	sign := ""
+	units, nanos := m.Units, m.Nanos
+	if units < 0 || nanos < 0 {
+		sign = "-"
+		units, nanos = -units, -nanos
+	}
+
+	// 小数点以下は末尾の0を除き、最低2桁は表示する
+	frac := strings.TrimRight(fmt.Sprintf("%09d", nanos), "0")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+
+	return fmt.Sprintf("%s%d.%s %s", sign, units, frac, m.CurrencyCode)
+}
+
+// 複数の通貨が混在する金額を通貨ごとに合計するための型
+// ゼロ値のまま使える
+type Sum struct {
+	totals map[string]*moneypb.Money
+}
+
+// 金額を通貨ごとの合計に加えるメソッド
+func (s *Sum) Add(m *moneypb.Money) error {
+	if s.totals == nil {
+		s.totals = make(map[string]*moneypb.Money)
+	}
+
+	total, ok := s.totals[m.CurrencyCode]
+	if !ok {
+		total = &moneypb.Money{CurrencyCode: m.CurrencyCode}
+	}
+
+	total, err := Add(total, m)
+	if err != nil {
+		return err
+	}
+	s.totals[m.CurrencyCode] = total
+
+	return nil
+}
+
+// 通貨ごとの合計を通貨コード順に返すメソッド
+func (s *Sum) Totals() []*moneypb.Money {
+	totals := make([]*moneypb.Money, 0, len(s.totals))
+	for _, total := range s.totals {
+		totals = append(totals, total)
+	}
+	slices.SortFunc(totals, func(a, b *moneypb.Money) int {
+		return strings.Compare(a.CurrencyCode, b.CurrencyCode)
+	})
+
+	return totals
+}
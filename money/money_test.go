@@ -0,0 +1,208 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/proto"
+)
+
+func usd(units int64, nanos int32) *moneypb.Money {
+	return &moneypb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *moneypb.Money
+	}{
+		{value: "56.99", want: usd(56, 990000000)},
+		{value: "  56.99 ", want: usd(56, 990000000)},
+		{value: "0", want: usd(0, 0)},
+		{value: "7", want: usd(7, 0)},
+		{value: "7.", want: usd(7, 0)},
+		{value: ".5", want: usd(0, 500000000)},
+		{value: "+1.25", want: usd(1, 250000000)},
+		{value: "-1.25", want: usd(-1, -250000000)},
+		{value: "-0.5", want: usd(0, -500000000)},
+		{value: "0.000000001", want: usd(0, 1)},
+		{value: "1.999999999", want: usd(1, 999999999)},
+		{value: "-9223372036854775808", want: usd(math.MinInt64, 0)},
+		{value: "9223372036854775807.999999999", want: usd(math.MaxInt64, 999999999)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse("USD", tt.value)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		" ",
+		".",
+		"-",
+		"+",
+		"--5",
+		"+-5",
+		"-+5",
+		"1.+5",
+		"1.-5",
+		"+.-5",
+		"1.0000000001", // 小数点以下10桁
+		"1.2.3",
+		"1,5",
+		"1e3",
+		"abc",
+		"5 USD",
+		"9223372036854775808", // int64を超える
+	}
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			if got, err := Parse("USD", value); !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("Parse(%q) = %v, %v, want ErrInvalidValue", value, got, err)
+			}
+		})
+	}
+
+	if _, err := Parse("usd", "1"); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Parse() with lowercase currency error = %v, want ErrInvalidValue", err)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *moneypb.Money
+		want *moneypb.Money
+	}{
+		{name: "no carry", a: usd(1, 100000000), b: usd(2, 200000000), want: usd(3, 300000000)},
+		{name: "nanos carry", a: usd(56, 990000000), b: usd(17, 990000000), want: usd(74, 980000000)},
+		{name: "negative nanos carry", a: usd(-1, -600000000), b: usd(-2, -700000000), want: usd(-4, -300000000)},
+		{name: "mixed signs", a: usd(5, 250000000), b: usd(-2, -500000000), want: usd(2, 750000000)},
+		{name: "mixed signs to negative", a: usd(1, 500000000), b: usd(-3, -250000000), want: usd(-1, -750000000)},
+		{name: "mixed signs to zero", a: usd(2, 500000000), b: usd(-2, -500000000), want: usd(0, 0)},
+		{name: "nanos only to negative", a: usd(0, 300000000), b: usd(0, -800000000), want: usd(0, -500000000)},
+		{name: "max without overflow", a: usd(math.MaxInt64-1, 600000000), b: usd(0, 600000000), want: usd(math.MaxInt64, 200000000)},
+		{name: "min without overflow", a: usd(math.MinInt64+1, -600000000), b: usd(0, -600000000), want: usd(math.MinInt64, -200000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Add(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("Add(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if err := Validate(got); err != nil {
+				t.Errorf("Add() returned an invalid value: %v", err)
+			}
+		})
+	}
+}
+
+func TestAddOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *moneypb.Money
+	}{
+		{name: "units overflow", a: usd(math.MaxInt64, 0), b: usd(1, 0)},
+		{name: "units underflow", a: usd(math.MinInt64, 0), b: usd(-1, 0)},
+		{name: "min plus min", a: usd(math.MinInt64, 0), b: usd(math.MinInt64, 0)},
+		{name: "carry overflow", a: usd(math.MaxInt64, 600000000), b: usd(0, 600000000)},
+		{name: "carry underflow", a: usd(math.MinInt64, -600000000), b: usd(0, -600000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Add(tt.a, tt.b); !errors.Is(err, ErrOverflow) {
+				t.Errorf("Add(%v, %v) = %v, %v, want ErrOverflow", tt.a, tt.b, got, err)
+			}
+		})
+	}
+}
+
+func TestAddCurrencyMismatch(t *testing.T) {
+	if _, err := Add(usd(1, 0), &moneypb.Money{CurrencyCode: "JPY", Units: 1}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *moneypb.Money
+		want int
+	}{
+		{name: "equal", a: usd(56, 990000000), b: usd(56, 990000000), want: 0},
+		{name: "units differ", a: usd(17, 990000000), b: usd(56, 0), want: -1},
+		{name: "nanos differ", a: usd(56, 990000000), b: usd(56, 500000000), want: 1},
+		{name: "negative nanos", a: usd(0, -500000000), b: usd(0, 0), want: -1},
+		{name: "negative values", a: usd(-2, -100000000), b: usd(-2, -200000000), want: 1},
+		{name: "mixed signs", a: usd(-1, 0), b: usd(0, 1), want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Compare(usd(1, 0), &moneypb.Money{CurrencyCode: "JPY", Units: 1}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Compare() error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		m    *moneypb.Money
+		want string
+	}{
+		{m: usd(56, 990000000), want: "56.99 USD"},
+		{m: usd(7, 0), want: "7.00 USD"},
+		{m: usd(0, 500000000), want: "0.50 USD"},
+		{m: usd(1, 1), want: "1.000000001 USD"},
+		{m: usd(-1, -250000000), want: "-1.25 USD"},
+		{m: usd(0, -500000000), want: "-0.50 USD"},
+		{m: nil, want: ""},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.m); got != tt.want {
+			t.Errorf("Format(%v) = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	var sum Sum
+	for _, m := range []*moneypb.Money{usd(56, 990000000), {CurrencyCode: "JPY", Units: 1500}, usd(17, 990000000), usd(29, 990000000)} {
+		if err := sum.Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []*moneypb.Money{{CurrencyCode: "JPY", Units: 1500}, usd(104, 970000000)}
+	got := sum.Totals()
+	if len(got) != len(want) {
+		t.Fatalf("Totals() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("Totals()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
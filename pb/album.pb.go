@@ -1,14 +1,18 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
 // 	protoc-gen-go v1.36.6
-// 	protoc        v3.12.4
+// 	protoc        (unknown)
 // source: proto/album.proto
 
 package pb
 
 import (
+	money "google.golang.org/genproto/googleapis/type/money"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,12 +25,65 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 文字列の条件の一致方法
+type MatchMode int32
+
+const (
+	MatchMode_MATCH_MODE_EXACT    MatchMode = 0 // 完全一致
+	MatchMode_MATCH_MODE_CONTAINS MatchMode = 1 // 部分一致
+)
+
+// Enum value maps for MatchMode.
+var (
+	MatchMode_name = map[int32]string{
+		0: "MATCH_MODE_EXACT",
+		1: "MATCH_MODE_CONTAINS",
+	}
+	MatchMode_value = map[string]int32{
+		"MATCH_MODE_EXACT":    0,
+		"MATCH_MODE_CONTAINS": 1,
+	}
+)
+
+func (x MatchMode) Enum() *MatchMode {
+	p := new(MatchMode)
+	*p = x
+	return p
+}
+
+func (x MatchMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_album_proto_enumTypes[0].Descriptor()
+}
+
+func (MatchMode) Type() protoreflect.EnumType {
+	return &file_proto_album_proto_enumTypes[0]
+}
+
+func (x MatchMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchMode.Descriptor instead.
+func (MatchMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{0}
+}
+
 // Albumの定義
 type Album struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Artist        string                 `protobuf:"bytes,2,opt,name=artist,proto3" json:"artist,omitempty"`
-	Price         float32                `protobuf:"fixed32,3,opt,name=price,proto3" json:"price,omitempty"`
+	Price         *money.Money           `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"` // 通貨コードと整数部・小数部（ナノ単位）で表す価格
+	Id            string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`        // サーバーが採番するID
+	ReleaseYear   int32                  `protobuf:"varint,5,opt,name=release_year,json=releaseYear,proto3" json:"release_year,omitempty"`
+	Genre         string                 `protobuf:"bytes,6,opt,name=genre,proto3" json:"genre,omitempty"`
+	Tracks        []*Track               `protobuf:"bytes,7,rep,name=tracks,proto3" json:"tracks,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // サーバーが設定する作成日時
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // サーバーが設定する更新日時
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -75,35 +132,79 @@ func (x *Album) GetArtist() string {
 	return ""
 }
 
-func (x *Album) GetPrice() float32 {
+func (x *Album) GetPrice() *money.Money {
 	if x != nil {
 		return x.Price
 	}
+	return nil
+}
+
+func (x *Album) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Album) GetReleaseYear() int32 {
+	if x != nil {
+		return x.ReleaseYear
+	}
 	return 0
 }
 
-// GetAlbumのリクエストとレスポンス
-type GetAlbumRequest struct {
+func (x *Album) GetGenre() string {
+	if x != nil {
+		return x.Genre
+	}
+	return ""
+}
+
+func (x *Album) GetTracks() []*Track {
+	if x != nil {
+		return x.Tracks
+	}
+	return nil
+}
+
+func (x *Album) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Album) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// Albumに収録されている曲の定義
+type Track struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"` // トラック番号
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Duration      *durationpb.Duration   `protobuf:"bytes,3,opt,name=duration,proto3" json:"duration,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAlbumRequest) Reset() {
-	*x = GetAlbumRequest{}
+func (x *Track) Reset() {
+	*x = Track{}
 	mi := &file_proto_album_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAlbumRequest) String() string {
+func (x *Track) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAlbumRequest) ProtoMessage() {}
+func (*Track) ProtoMessage() {}
 
-func (x *GetAlbumRequest) ProtoReflect() protoreflect.Message {
+func (x *Track) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -115,39 +216,55 @@ func (x *GetAlbumRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAlbumRequest.ProtoReflect.Descriptor instead.
-func (*GetAlbumRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Track.ProtoReflect.Descriptor instead.
+func (*Track) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *GetAlbumRequest) GetTitle() string {
+func (x *Track) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Track) GetTitle() string {
 	if x != nil {
 		return x.Title
 	}
 	return ""
 }
 
-type GetAlbumResponse struct {
+func (x *Track) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+// GetAlbumのリクエストとレスポンス
+type GetAlbumRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"` // 同じタイトルのアルバムが複数ある場合は最初に登録されたものを返す
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`       // 指定した場合はtitleより優先する
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAlbumResponse) Reset() {
-	*x = GetAlbumResponse{}
+func (x *GetAlbumRequest) Reset() {
+	*x = GetAlbumRequest{}
 	mi := &file_proto_album_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAlbumResponse) String() string {
+func (x *GetAlbumRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAlbumResponse) ProtoMessage() {}
+func (*GetAlbumRequest) ProtoMessage() {}
 
-func (x *GetAlbumResponse) ProtoReflect() protoreflect.Message {
+func (x *GetAlbumRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -159,40 +276,46 @@ func (x *GetAlbumResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAlbumResponse.ProtoReflect.Descriptor instead.
-func (*GetAlbumResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetAlbumRequest.ProtoReflect.Descriptor instead.
+func (*GetAlbumRequest) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetAlbumResponse) GetAlbum() *Album {
+func (x *GetAlbumRequest) GetTitle() string {
 	if x != nil {
-		return x.Album
+		return x.Title
 	}
-	return nil
+	return ""
 }
 
-// ListAlbumsのリクエストとレスポンス
-type ListAlbumsRequest struct {
+func (x *GetAlbumRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetAlbumResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Artist        string                 `protobuf:"bytes,1,opt,name=artist,proto3" json:"artist,omitempty"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAlbumsRequest) Reset() {
-	*x = ListAlbumsRequest{}
+func (x *GetAlbumResponse) Reset() {
+	*x = GetAlbumResponse{}
 	mi := &file_proto_album_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAlbumsRequest) String() string {
+func (x *GetAlbumResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAlbumsRequest) ProtoMessage() {}
+func (*GetAlbumResponse) ProtoMessage() {}
 
-func (x *ListAlbumsRequest) ProtoReflect() protoreflect.Message {
+func (x *GetAlbumResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -204,39 +327,45 @@ func (x *ListAlbumsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAlbumsRequest.ProtoReflect.Descriptor instead.
-func (*ListAlbumsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetAlbumResponse.ProtoReflect.Descriptor instead.
+func (*GetAlbumResponse) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ListAlbumsRequest) GetArtist() string {
+func (x *GetAlbumResponse) GetAlbum() *Album {
 	if x != nil {
-		return x.Artist
+		return x.Album
 	}
-	return ""
+	return nil
 }
 
-type ListAlbumsResponse struct {
+// アルバムの絞り込み条件（指定しなかった項目は条件として扱わない）
+type AlbumFilter struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Artist        string                 `protobuf:"bytes,2,opt,name=artist,proto3" json:"artist,omitempty"`
+	MatchMode     MatchMode              `protobuf:"varint,3,opt,name=match_mode,json=matchMode,proto3,enum=album.MatchMode" json:"match_mode,omitempty"` // titleとartistの一致方法
+	IgnoreCase    bool                   `protobuf:"varint,4,opt,name=ignore_case,json=ignoreCase,proto3" json:"ignore_case,omitempty"`                   // titleとartistの大文字・小文字を区別しない
+	MinPrice      *money.Money           `protobuf:"bytes,5,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`                          // 指定した金額以上（通貨が異なるアルバムは含まない）
+	MaxPrice      *money.Money           `protobuf:"bytes,6,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`                          // 指定した金額以下（通貨が異なるアルバムは含まない）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAlbumsResponse) Reset() {
-	*x = ListAlbumsResponse{}
+func (x *AlbumFilter) Reset() {
+	*x = AlbumFilter{}
 	mi := &file_proto_album_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAlbumsResponse) String() string {
+func (x *AlbumFilter) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAlbumsResponse) ProtoMessage() {}
+func (*AlbumFilter) ProtoMessage() {}
 
-func (x *ListAlbumsResponse) ProtoReflect() protoreflect.Message {
+func (x *AlbumFilter) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -248,40 +377,79 @@ func (x *ListAlbumsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAlbumsResponse.ProtoReflect.Descriptor instead.
-func (*ListAlbumsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AlbumFilter.ProtoReflect.Descriptor instead.
+func (*AlbumFilter) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListAlbumsResponse) GetAlbum() *Album {
+func (x *AlbumFilter) GetTitle() string {
 	if x != nil {
-		return x.Album
+		return x.Title
+	}
+	return ""
+}
+
+func (x *AlbumFilter) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
+}
+
+func (x *AlbumFilter) GetMatchMode() MatchMode {
+	if x != nil {
+		return x.MatchMode
+	}
+	return MatchMode_MATCH_MODE_EXACT
+}
+
+func (x *AlbumFilter) GetIgnoreCase() bool {
+	if x != nil {
+		return x.IgnoreCase
+	}
+	return false
+}
+
+func (x *AlbumFilter) GetMinPrice() *money.Money {
+	if x != nil {
+		return x.MinPrice
 	}
 	return nil
 }
 
-// GetTotalAmountのリクエストとレスポンス
-type GetTotalAmountRequest struct {
+func (x *AlbumFilter) GetMaxPrice() *money.Money {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return nil
+}
+
+// ListAlbumsのリクエストとレスポンス
+type ListAlbumsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Artist        string                 `protobuf:"bytes,1,opt,name=artist,proto3" json:"artist,omitempty"` // filter.artistの完全一致と同じ（filterと同時には指定できない）
+	Filter        *AlbumFilter           `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 1ページの件数（省略時は50、最大1000）
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // 前のページの最後のレスポンスで受け取ったnext_page_token
+	OrderBy       string                 `protobuf:"bytes,5,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // "title", "artist", "price"のいずれか（" desc"を付けると降順、省略時は登録順）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTotalAmountRequest) Reset() {
-	*x = GetTotalAmountRequest{}
+func (x *ListAlbumsRequest) Reset() {
+	*x = ListAlbumsRequest{}
 	mi := &file_proto_album_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTotalAmountRequest) String() string {
+func (x *ListAlbumsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTotalAmountRequest) ProtoMessage() {}
+func (*ListAlbumsRequest) ProtoMessage() {}
 
-func (x *GetTotalAmountRequest) ProtoReflect() protoreflect.Message {
+func (x *ListAlbumsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -293,41 +461,68 @@ func (x *GetTotalAmountRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTotalAmountRequest.ProtoReflect.Descriptor instead.
-func (*GetTotalAmountRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListAlbumsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlbumsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetTotalAmountRequest) GetTitle() string {
+func (x *ListAlbumsRequest) GetArtist() string {
 	if x != nil {
-		return x.Title
+		return x.Artist
 	}
 	return ""
 }
 
-type GetTotalAmountResponse struct {
+func (x *ListAlbumsRequest) GetFilter() *AlbumFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListAlbumsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAlbumsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAlbumsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+type ListAlbumsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AlbumCount    int32                  `protobuf:"varint,1,opt,name=album_count,json=albumCount,proto3" json:"album_count,omitempty"`
-	TotalAmount   float32                `protobuf:"fixed32,2,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // ページの最後のレスポンスにだけ設定する（次のページがない場合は空）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTotalAmountResponse) Reset() {
-	*x = GetTotalAmountResponse{}
+func (x *ListAlbumsResponse) Reset() {
+	*x = ListAlbumsResponse{}
 	mi := &file_proto_album_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTotalAmountResponse) String() string {
+func (x *ListAlbumsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTotalAmountResponse) ProtoMessage() {}
+func (*ListAlbumsResponse) ProtoMessage() {}
 
-func (x *GetTotalAmountResponse) ProtoReflect() protoreflect.Message {
+func (x *ListAlbumsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -339,54 +534,51 @@ func (x *GetTotalAmountResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTotalAmountResponse.ProtoReflect.Descriptor instead.
-func (*GetTotalAmountResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListAlbumsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlbumsResponse) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetTotalAmountResponse) GetAlbumCount() int32 {
-	if x != nil {
-		return x.AlbumCount
-	}
-	return 0
-}
-
-func (x *GetTotalAmountResponse) GetTotalAmount() float32 {
+func (x *ListAlbumsResponse) GetAlbum() *Album {
 	if x != nil {
-		return x.TotalAmount
+		return x.Album
 	}
-	return 0
+	return nil
 }
 
-func (x *GetTotalAmountResponse) GetMessage() string {
+func (x *ListAlbumsResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Message
+		return x.NextPageToken
 	}
 	return ""
 }
 
-// UploadAndNotifyのリクエストとレスポンス
-type UploadAndNotifyRequest struct {
+// SearchAlbumsのリクエストとレスポンス
+type SearchAlbumsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	Filter        *AlbumFilter           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 1ページの件数（省略時は50、最大1000）
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // 前のページのレスポンスで受け取ったnext_page_token
+	OrderBy       string                 `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`       // "title", "artist", "price"のいずれか（" desc"を付けると降順、省略時は登録順）queryとは同時に指定できない
+	Query         string                 `protobuf:"bytes,5,opt,name=query,proto3" json:"query,omitempty"`                          // 全文検索のキーワード（例: "coltrane love"）タイトル・アーティスト・ジャンル・曲名から前方一致やスペルミスも含めて検索する
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UploadAndNotifyRequest) Reset() {
-	*x = UploadAndNotifyRequest{}
+func (x *SearchAlbumsRequest) Reset() {
+	*x = SearchAlbumsRequest{}
 	mi := &file_proto_album_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UploadAndNotifyRequest) String() string {
+func (x *SearchAlbumsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UploadAndNotifyRequest) ProtoMessage() {}
+func (*SearchAlbumsRequest) ProtoMessage() {}
 
-func (x *UploadAndNotifyRequest) ProtoReflect() protoreflect.Message {
+func (x *SearchAlbumsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_album_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -398,28 +590,343 @@ func (x *UploadAndNotifyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UploadAndNotifyRequest.ProtoReflect.Descriptor instead.
-func (*UploadAndNotifyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SearchAlbumsRequest.ProtoReflect.Descriptor instead.
+func (*SearchAlbumsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_album_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *UploadAndNotifyRequest) GetAlbum() *Album {
+func (x *SearchAlbumsRequest) GetFilter() *AlbumFilter {
 	if x != nil {
-		return x.Album
+		return x.Filter
 	}
 	return nil
 }
 
-type UploadAndNotifyResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SearchAlbumsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchAlbumsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchAlbumsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *SearchAlbumsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type SearchAlbumsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Albums        []*Album               `protobuf:"bytes,1,rep,name=albums,proto3" json:"albums,omitempty"`                                      // queryを指定しなかった場合の結果
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // 次のページがない場合は空
+	TotalSize     int32                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`              // 条件に一致するアルバムの総数
+	Results       []*SearchResult        `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`                                    // queryを指定した場合の結果（関連度の高い順）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchAlbumsResponse) Reset() {
+	*x = SearchAlbumsResponse{}
+	mi := &file_proto_album_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchAlbumsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAlbumsResponse) ProtoMessage() {}
+
+func (x *SearchAlbumsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAlbumsResponse.ProtoReflect.Descriptor instead.
+func (*SearchAlbumsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SearchAlbumsResponse) GetAlbums() []*Album {
+	if x != nil {
+		return x.Albums
+	}
+	return nil
+}
+
+func (x *SearchAlbumsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *SearchAlbumsResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *SearchAlbumsResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// 全文検索の結果
+type SearchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`                                 // 関連度（大きいほどクエリに近い）
+	MatchedTerms  []string               `protobuf:"bytes,3,rep,name=matched_terms,json=matchedTerms,proto3" json:"matched_terms,omitempty"` // クエリに一致した索引の単語
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_proto_album_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchResult) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchResult) GetMatchedTerms() []string {
+	if x != nil {
+		return x.MatchedTerms
+	}
+	return nil
+}
+
+// GetTotalAmountのリクエストとレスポンス
+type GetTotalAmountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"` // 指定した場合はtitleより優先する
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTotalAmountRequest) Reset() {
+	*x = GetTotalAmountRequest{}
+	mi := &file_proto_album_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTotalAmountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTotalAmountRequest) ProtoMessage() {}
+
+func (x *GetTotalAmountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTotalAmountRequest.ProtoReflect.Descriptor instead.
+func (*GetTotalAmountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetTotalAmountRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *GetTotalAmountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetTotalAmountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlbumCount    int32                  `protobuf:"varint,1,opt,name=album_count,json=albumCount,proto3" json:"album_count,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Totals        []*money.Money         `protobuf:"bytes,4,rep,name=totals,proto3" json:"totals,omitempty"` // 通貨ごとの合計金額
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTotalAmountResponse) Reset() {
+	*x = GetTotalAmountResponse{}
+	mi := &file_proto_album_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTotalAmountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTotalAmountResponse) ProtoMessage() {}
+
+func (x *GetTotalAmountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTotalAmountResponse.ProtoReflect.Descriptor instead.
+func (*GetTotalAmountResponse) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetTotalAmountResponse) GetAlbumCount() int32 {
+	if x != nil {
+		return x.AlbumCount
+	}
+	return 0
+}
+
+func (x *GetTotalAmountResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetTotalAmountResponse) GetTotals() []*money.Money {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+// UploadAndNotifyのリクエストとレスポンス
+type UploadAndNotifyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAndNotifyRequest) Reset() {
+	*x = UploadAndNotifyRequest{}
+	mi := &file_proto_album_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAndNotifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAndNotifyRequest) ProtoMessage() {}
+
+func (x *UploadAndNotifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAndNotifyRequest.ProtoReflect.Descriptor instead.
+func (*UploadAndNotifyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UploadAndNotifyRequest) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+type UploadAndNotifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UploadAndNotifyResponse) Reset() {
 	*x = UploadAndNotifyResponse{}
-	mi := &file_proto_album_proto_msgTypes[8]
+	mi := &file_proto_album_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -431,7 +938,7 @@ func (x *UploadAndNotifyResponse) String() string {
 func (*UploadAndNotifyResponse) ProtoMessage() {}
 
 func (x *UploadAndNotifyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_album_proto_msgTypes[8]
+	mi := &file_proto_album_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,7 +951,7 @@ func (x *UploadAndNotifyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadAndNotifyResponse.ProtoReflect.Descriptor instead.
 func (*UploadAndNotifyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_album_proto_rawDescGZIP(), []int{8}
+	return file_proto_album_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UploadAndNotifyResponse) GetMessage() string {
@@ -454,40 +961,376 @@ func (x *UploadAndNotifyResponse) GetMessage() string {
 	return ""
 }
 
+// CreateAlbumのリクエストとレスポンス
+type CreateAlbumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlbumRequest) Reset() {
+	*x = CreateAlbumRequest{}
+	mi := &file_proto_album_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlbumRequest) ProtoMessage() {}
+
+func (x *CreateAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlbumRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateAlbumRequest) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+type CreateAlbumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlbumResponse) Reset() {
+	*x = CreateAlbumResponse{}
+	mi := &file_proto_album_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlbumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlbumResponse) ProtoMessage() {}
+
+func (x *CreateAlbumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlbumResponse.ProtoReflect.Descriptor instead.
+func (*CreateAlbumResponse) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateAlbumResponse) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+// UpdateAlbumのリクエストとレスポンス
+type UpdateAlbumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`                             // idで更新対象のアルバムを指定する（update_maskを反映した後のアルバムをハンドラーで検証する）
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"` // 更新するフィールド（省略時はサーバーが設定するフィールド以外のすべて）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlbumRequest) Reset() {
+	*x = UpdateAlbumRequest{}
+	mi := &file_proto_album_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlbumRequest) ProtoMessage() {}
+
+func (x *UpdateAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlbumRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateAlbumRequest) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+func (x *UpdateAlbumRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type UpdateAlbumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Album         *Album                 `protobuf:"bytes,1,opt,name=album,proto3" json:"album,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlbumResponse) Reset() {
+	*x = UpdateAlbumResponse{}
+	mi := &file_proto_album_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlbumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlbumResponse) ProtoMessage() {}
+
+func (x *UpdateAlbumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlbumResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAlbumResponse) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateAlbumResponse) GetAlbum() *Album {
+	if x != nil {
+		return x.Album
+	}
+	return nil
+}
+
+// DeleteAlbumのリクエストとレスポンス
+type DeleteAlbumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlbumRequest) Reset() {
+	*x = DeleteAlbumRequest{}
+	mi := &file_proto_album_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlbumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlbumRequest) ProtoMessage() {}
+
+func (x *DeleteAlbumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlbumRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlbumRequest) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteAlbumRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteAlbumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAlbumResponse) Reset() {
+	*x = DeleteAlbumResponse{}
+	mi := &file_proto_album_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAlbumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAlbumResponse) ProtoMessage() {}
+
+func (x *DeleteAlbumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_album_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAlbumResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlbumResponse) Descriptor() ([]byte, []int) {
+	return file_proto_album_proto_rawDescGZIP(), []int{19}
+}
+
 var File_proto_album_proto protoreflect.FileDescriptor
 
 const file_proto_album_proto_rawDesc = "" +
 	"\n" +
-	"\x11proto/album.proto\x12\x05album\"K\n" +
-	"\x05Album\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12\x16\n" +
-	"\x06artist\x18\x02 \x01(\tR\x06artist\x12\x14\n" +
-	"\x05price\x18\x03 \x01(\x02R\x05price\"'\n" +
-	"\x0fGetAlbumRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\"6\n" +
+	"\x11proto/album.proto\x12\x05album\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17google/type/money.proto\x1a\x14proto/validate.proto\"\x9f\x03\n" +
+	"\x05Album\x12#\n" +
+	"\x05title\x18\x01 \x01(\tB\r\xc2\xf3\x18\t\b\x01R\x05\x10\xc8\x01 \x01R\x05title\x12#\n" +
+	"\x06artist\x18\x02 \x01(\tB\v\xc2\xf3\x18\aR\x05\x10\xc8\x01 \x01R\x06artist\x122\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2\x12.google.type.MoneyB\b\xc2\xf3\x18\x04b\x02\b\x01R\x05price\x12\x18\n" +
+	"\x02id\x18\x04 \x01(\tB\b\xc2\xf3\x18\x04R\x02\x10@R\x02id\x12/\n" +
+	"\frelease_year\x18\x05 \x01(\x05B\f\xc2\xf3\x18\bZ\x06\b\xe8\a\x10\x8fNR\vreleaseYear\x12 \n" +
+	"\x05genre\x18\x06 \x01(\tB\n" +
+	"\xc2\xf3\x18\x06R\x04\x10d \x01R\x05genre\x12/\n" +
+	"\x06tracks\x18\a \x03(\v2\f.album.TrackB\t\xc2\xf3\x18\x05r\x03\b\xf4\x03R\x06tracks\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtJ\x04\b\x03\x10\x04\"\x92\x01\n" +
+	"\x05Track\x12#\n" +
+	"\x06number\x18\x01 \x01(\x05B\v\xc2\xf3\x18\aZ\x05\b\x01\x10\xe7\aR\x06number\x12#\n" +
+	"\x05title\x18\x02 \x01(\tB\r\xc2\xf3\x18\t\b\x01R\x05\x10\xc8\x01 \x01R\x05title\x12?\n" +
+	"\bduration\x18\x03 \x01(\v2\x19.google.protobuf.DurationB\b\xc2\xf3\x18\x04j\x02\b\x01R\bduration\"L\n" +
+	"\x0fGetAlbumRequest\x12\x1f\n" +
+	"\x05title\x18\x01 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\xc8\x01R\x05title\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xc2\xf3\x18\x04R\x02\x10@R\x02id\"6\n" +
 	"\x10GetAlbumResponse\x12\"\n" +
-	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\"+\n" +
-	"\x11ListAlbumsRequest\x12\x16\n" +
-	"\x06artist\x18\x01 \x01(\tR\x06artist\"8\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\"\x85\x02\n" +
+	"\vAlbumFilter\x12\x1f\n" +
+	"\x05title\x18\x01 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\xc8\x01R\x05title\x12!\n" +
+	"\x06artist\x18\x02 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\xc8\x01R\x06artist\x12/\n" +
+	"\n" +
+	"match_mode\x18\x03 \x01(\x0e2\x10.album.MatchModeR\tmatchMode\x12\x1f\n" +
+	"\vignore_case\x18\x04 \x01(\bR\n" +
+	"ignoreCase\x12/\n" +
+	"\tmin_price\x18\x05 \x01(\v2\x12.google.type.MoneyR\bminPrice\x12/\n" +
+	"\tmax_price\x18\x06 \x01(\v2\x12.google.type.MoneyR\bmaxPrice\"\xd8\x01\n" +
+	"\x11ListAlbumsRequest\x12!\n" +
+	"\x06artist\x18\x01 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\xc8\x01R\x06artist\x12*\n" +
+	"\x06filter\x18\x02 \x01(\v2\x12.album.AlbumFilterR\x06filter\x12%\n" +
+	"\tpage_size\x18\x03 \x01(\x05B\b\xc2\xf3\x18\x04Z\x02\b\x00R\bpageSize\x12(\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\x80\bR\tpageToken\x12#\n" +
+	"\border_by\x18\x05 \x01(\tB\b\xc2\xf3\x18\x04R\x02\x10dR\aorderBy\"`\n" +
 	"\x12ListAlbumsResponse\x12\"\n" +
-	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\"-\n" +
-	"\x15GetTotalAmountRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\"v\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xd8\x01\n" +
+	"\x13SearchAlbumsRequest\x12*\n" +
+	"\x06filter\x18\x01 \x01(\v2\x12.album.AlbumFilterR\x06filter\x12%\n" +
+	"\tpage_size\x18\x02 \x01(\x05B\b\xc2\xf3\x18\x04Z\x02\b\x00R\bpageSize\x12(\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\x80\bR\tpageToken\x12#\n" +
+	"\border_by\x18\x04 \x01(\tB\b\xc2\xf3\x18\x04R\x02\x10dR\aorderBy\x12\x1f\n" +
+	"\x05query\x18\x05 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\xf4\x03R\x05query\"\xb2\x01\n" +
+	"\x14SearchAlbumsResponse\x12$\n" +
+	"\x06albums\x18\x01 \x03(\v2\f.album.AlbumR\x06albums\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x05R\ttotalSize\x12-\n" +
+	"\aresults\x18\x04 \x03(\v2\x13.album.SearchResultR\aresults\"m\n" +
+	"\fSearchResult\x12\"\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12#\n" +
+	"\rmatched_terms\x18\x03 \x03(\tR\fmatchedTerms\"R\n" +
+	"\x15GetTotalAmountRequest\x12\x1f\n" +
+	"\x05title\x18\x01 \x01(\tB\t\xc2\xf3\x18\x05R\x03\x10\xc8\x01R\x05title\x12\x18\n" +
+	"\x02id\x18\x02 \x01(\tB\b\xc2\xf3\x18\x04R\x02\x10@R\x02id\"\x93\x01\n" +
 	"\x16GetTotalAmountResponse\x12\x1f\n" +
 	"\valbum_count\x18\x01 \x01(\x05R\n" +
-	"albumCount\x12!\n" +
-	"\ftotal_amount\x18\x02 \x01(\x02R\vtotalAmount\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage\"<\n" +
-	"\x16UploadAndNotifyRequest\x12\"\n" +
-	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\"3\n" +
+	"albumCount\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12*\n" +
+	"\x06totals\x18\x04 \x03(\v2\x12.google.type.MoneyR\x06totalsJ\x04\b\x02\x10\x03R\ftotal_amount\"D\n" +
+	"\x16UploadAndNotifyRequest\x12*\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumB\x06\xc2\xf3\x18\x02\b\x01R\x05album\"3\n" +
 	"\x17UploadAndNotifyResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\xb7\x02\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"@\n" +
+	"\x12CreateAlbumRequest\x12*\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumB\x06\xc2\xf3\x18\x02\b\x01R\x05album\"9\n" +
+	"\x13CreateAlbumResponse\x12\"\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\"\x7f\n" +
+	"\x12UpdateAlbumRequest\x12,\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumB\b\xc2\xf3\x18\x04\b\x01\x10\x01R\x05album\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"9\n" +
+	"\x13UpdateAlbumResponse\x12\"\n" +
+	"\x05album\x18\x01 \x01(\v2\f.album.AlbumR\x05album\"=\n" +
+	"\x12DeleteAlbumRequest\x12\x1a\n" +
+	"\x02id\x18\x02 \x01(\tB\n" +
+	"\xc2\xf3\x18\x06\b\x01R\x02\x10@R\x02idJ\x04\b\x01\x10\x02R\x05title\"\x15\n" +
+	"\x13DeleteAlbumResponse*:\n" +
+	"\tMatchMode\x12\x14\n" +
+	"\x10MATCH_MODE_EXACT\x10\x00\x12\x17\n" +
+	"\x13MATCH_MODE_CONTAINS\x10\x012\xd2\x04\n" +
 	"\fAlbumService\x12;\n" +
 	"\bGetAlbum\x12\x16.album.GetAlbumRequest\x1a\x17.album.GetAlbumResponse\x12C\n" +
 	"\n" +
 	"ListAlbums\x12\x18.album.ListAlbumsRequest\x1a\x19.album.ListAlbumsResponse0\x01\x12O\n" +
 	"\x0eGetTotalAmount\x12\x1c.album.GetTotalAmountRequest\x1a\x1d.album.GetTotalAmountResponse(\x01\x12T\n" +
-	"\x0fUploadAndNotify\x12\x1d.album.UploadAndNotifyRequest\x1a\x1e.album.UploadAndNotifyResponse(\x010\x01B\x06Z\x04./pbb\x06proto3"
+	"\x0fUploadAndNotify\x12\x1d.album.UploadAndNotifyRequest\x1a\x1e.album.UploadAndNotifyResponse(\x010\x01\x12D\n" +
+	"\vCreateAlbum\x12\x19.album.CreateAlbumRequest\x1a\x1a.album.CreateAlbumResponse\x12D\n" +
+	"\vUpdateAlbum\x12\x19.album.UpdateAlbumRequest\x1a\x1a.album.UpdateAlbumResponse\x12D\n" +
+	"\vDeleteAlbum\x12\x19.album.DeleteAlbumRequest\x1a\x1a.album.DeleteAlbumResponse\x12G\n" +
+	"\fSearchAlbums\x12\x1a.album.SearchAlbumsRequest\x1a\x1b.album.SearchAlbumsResponseB\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_proto_album_proto_rawDescOnce sync.Once
@@ -501,35 +1344,79 @@ func file_proto_album_proto_rawDescGZIP() []byte {
 	return file_proto_album_proto_rawDescData
 }
 
-var file_proto_album_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_album_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_album_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
 var file_proto_album_proto_goTypes = []any{
-	(*Album)(nil),                   // 0: album.Album
-	(*GetAlbumRequest)(nil),         // 1: album.GetAlbumRequest
-	(*GetAlbumResponse)(nil),        // 2: album.GetAlbumResponse
-	(*ListAlbumsRequest)(nil),       // 3: album.ListAlbumsRequest
-	(*ListAlbumsResponse)(nil),      // 4: album.ListAlbumsResponse
-	(*GetTotalAmountRequest)(nil),   // 5: album.GetTotalAmountRequest
-	(*GetTotalAmountResponse)(nil),  // 6: album.GetTotalAmountResponse
-	(*UploadAndNotifyRequest)(nil),  // 7: album.UploadAndNotifyRequest
-	(*UploadAndNotifyResponse)(nil), // 8: album.UploadAndNotifyResponse
+	(MatchMode)(0),                  // 0: album.MatchMode
+	(*Album)(nil),                   // 1: album.Album
+	(*Track)(nil),                   // 2: album.Track
+	(*GetAlbumRequest)(nil),         // 3: album.GetAlbumRequest
+	(*GetAlbumResponse)(nil),        // 4: album.GetAlbumResponse
+	(*AlbumFilter)(nil),             // 5: album.AlbumFilter
+	(*ListAlbumsRequest)(nil),       // 6: album.ListAlbumsRequest
+	(*ListAlbumsResponse)(nil),      // 7: album.ListAlbumsResponse
+	(*SearchAlbumsRequest)(nil),     // 8: album.SearchAlbumsRequest
+	(*SearchAlbumsResponse)(nil),    // 9: album.SearchAlbumsResponse
+	(*SearchResult)(nil),            // 10: album.SearchResult
+	(*GetTotalAmountRequest)(nil),   // 11: album.GetTotalAmountRequest
+	(*GetTotalAmountResponse)(nil),  // 12: album.GetTotalAmountResponse
+	(*UploadAndNotifyRequest)(nil),  // 13: album.UploadAndNotifyRequest
+	(*UploadAndNotifyResponse)(nil), // 14: album.UploadAndNotifyResponse
+	(*CreateAlbumRequest)(nil),      // 15: album.CreateAlbumRequest
+	(*CreateAlbumResponse)(nil),     // 16: album.CreateAlbumResponse
+	(*UpdateAlbumRequest)(nil),      // 17: album.UpdateAlbumRequest
+	(*UpdateAlbumResponse)(nil),     // 18: album.UpdateAlbumResponse
+	(*DeleteAlbumRequest)(nil),      // 19: album.DeleteAlbumRequest
+	(*DeleteAlbumResponse)(nil),     // 20: album.DeleteAlbumResponse
+	(*money.Money)(nil),             // 21: google.type.Money
+	(*timestamppb.Timestamp)(nil),   // 22: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),     // 23: google.protobuf.Duration
+	(*fieldmaskpb.FieldMask)(nil),   // 24: google.protobuf.FieldMask
 }
 var file_proto_album_proto_depIdxs = []int32{
-	0, // 0: album.GetAlbumResponse.album:type_name -> album.Album
-	0, // 1: album.ListAlbumsResponse.album:type_name -> album.Album
-	0, // 2: album.UploadAndNotifyRequest.album:type_name -> album.Album
-	1, // 3: album.AlbumService.GetAlbum:input_type -> album.GetAlbumRequest
-	3, // 4: album.AlbumService.ListAlbums:input_type -> album.ListAlbumsRequest
-	5, // 5: album.AlbumService.GetTotalAmount:input_type -> album.GetTotalAmountRequest
-	7, // 6: album.AlbumService.UploadAndNotify:input_type -> album.UploadAndNotifyRequest
-	2, // 7: album.AlbumService.GetAlbum:output_type -> album.GetAlbumResponse
-	4, // 8: album.AlbumService.ListAlbums:output_type -> album.ListAlbumsResponse
-	6, // 9: album.AlbumService.GetTotalAmount:output_type -> album.GetTotalAmountResponse
-	8, // 10: album.AlbumService.UploadAndNotify:output_type -> album.UploadAndNotifyResponse
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	21, // 0: album.Album.price:type_name -> google.type.Money
+	2,  // 1: album.Album.tracks:type_name -> album.Track
+	22, // 2: album.Album.created_at:type_name -> google.protobuf.Timestamp
+	22, // 3: album.Album.updated_at:type_name -> google.protobuf.Timestamp
+	23, // 4: album.Track.duration:type_name -> google.protobuf.Duration
+	1,  // 5: album.GetAlbumResponse.album:type_name -> album.Album
+	0,  // 6: album.AlbumFilter.match_mode:type_name -> album.MatchMode
+	21, // 7: album.AlbumFilter.min_price:type_name -> google.type.Money
+	21, // 8: album.AlbumFilter.max_price:type_name -> google.type.Money
+	5,  // 9: album.ListAlbumsRequest.filter:type_name -> album.AlbumFilter
+	1,  // 10: album.ListAlbumsResponse.album:type_name -> album.Album
+	5,  // 11: album.SearchAlbumsRequest.filter:type_name -> album.AlbumFilter
+	1,  // 12: album.SearchAlbumsResponse.albums:type_name -> album.Album
+	10, // 13: album.SearchAlbumsResponse.results:type_name -> album.SearchResult
+	1,  // 14: album.SearchResult.album:type_name -> album.Album
+	21, // 15: album.GetTotalAmountResponse.totals:type_name -> google.type.Money
+	1,  // 16: album.UploadAndNotifyRequest.album:type_name -> album.Album
+	1,  // 17: album.CreateAlbumRequest.album:type_name -> album.Album
+	1,  // 18: album.CreateAlbumResponse.album:type_name -> album.Album
+	1,  // 19: album.UpdateAlbumRequest.album:type_name -> album.Album
+	24, // 20: album.UpdateAlbumRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 21: album.UpdateAlbumResponse.album:type_name -> album.Album
+	3,  // 22: album.AlbumService.GetAlbum:input_type -> album.GetAlbumRequest
+	6,  // 23: album.AlbumService.ListAlbums:input_type -> album.ListAlbumsRequest
+	11, // 24: album.AlbumService.GetTotalAmount:input_type -> album.GetTotalAmountRequest
+	13, // 25: album.AlbumService.UploadAndNotify:input_type -> album.UploadAndNotifyRequest
+	15, // 26: album.AlbumService.CreateAlbum:input_type -> album.CreateAlbumRequest
+	17, // 27: album.AlbumService.UpdateAlbum:input_type -> album.UpdateAlbumRequest
+	19, // 28: album.AlbumService.DeleteAlbum:input_type -> album.DeleteAlbumRequest
+	8,  // 29: album.AlbumService.SearchAlbums:input_type -> album.SearchAlbumsRequest
+	4,  // 30: album.AlbumService.GetAlbum:output_type -> album.GetAlbumResponse
+	7,  // 31: album.AlbumService.ListAlbums:output_type -> album.ListAlbumsResponse
+	12, // 32: album.AlbumService.GetTotalAmount:output_type -> album.GetTotalAmountResponse
+	14, // 33: album.AlbumService.UploadAndNotify:output_type -> album.UploadAndNotifyResponse
+	16, // 34: album.AlbumService.CreateAlbum:output_type -> album.CreateAlbumResponse
+	18, // 35: album.AlbumService.UpdateAlbum:output_type -> album.UpdateAlbumResponse
+	20, // 36: album.AlbumService.DeleteAlbum:output_type -> album.DeleteAlbumResponse
+	9,  // 37: album.AlbumService.SearchAlbums:output_type -> album.SearchAlbumsResponse
+	30, // [30:38] is the sub-list for method output_type
+	22, // [22:30] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_proto_album_proto_init() }
@@ -537,18 +1424,20 @@ func file_proto_album_proto_init() {
 	if File_proto_album_proto != nil {
 		return
 	}
+	file_proto_validate_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_album_proto_rawDesc), len(file_proto_album_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   9,
+			NumEnums:      1,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_album_proto_goTypes,
 		DependencyIndexes: file_proto_album_proto_depIdxs,
+		EnumInfos:         file_proto_album_proto_enumTypes,
 		MessageInfos:      file_proto_album_proto_msgTypes,
 	}.Build()
 	File_proto_album_proto = out.File
@@ -0,0 +1,561 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/validate.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// フィールドに付ける検証ルール（protovalidateの書き方にならい、このサービスで必要なルールだけを定義する）
+// 例: string title = 1 [(album.validate.field).string = {min_len: 1, max_len: 200}];
+//
+// requiredを除くルールは、フィールドがゼロ値（空文字列・0・未設定のメッセージ）の場合は確認しない
+type FieldRules struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Required bool                   `protobuf:"varint,1,opt,name=required,proto3" json:"required,omitempty"` // ゼロ値を許可しない（メッセージの場合は設定されていること）
+	Skip     bool                   `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`         // メッセージの中身を検証しない（部分的な値を受け取るフィールドに付ける）
+	// Types that are valid to be assigned to Type:
+	//
+	//	*FieldRules_String_
+	//	*FieldRules_Int32
+	//	*FieldRules_Money
+	//	*FieldRules_Duration
+	//	*FieldRules_Repeated
+	Type          isFieldRules_Type `protobuf_oneof:"type"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldRules) Reset() {
+	*x = FieldRules{}
+	mi := &file_proto_validate_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldRules) ProtoMessage() {}
+
+func (x *FieldRules) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_validate_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldRules.ProtoReflect.Descriptor instead.
+func (*FieldRules) Descriptor() ([]byte, []int) {
+	return file_proto_validate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldRules) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *FieldRules) GetSkip() bool {
+	if x != nil {
+		return x.Skip
+	}
+	return false
+}
+
+func (x *FieldRules) GetType() isFieldRules_Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *FieldRules) GetString_() *StringRules {
+	if x != nil {
+		if x, ok := x.Type.(*FieldRules_String_); ok {
+			return x.String_
+		}
+	}
+	return nil
+}
+
+func (x *FieldRules) GetInt32() *Int32Rules {
+	if x != nil {
+		if x, ok := x.Type.(*FieldRules_Int32); ok {
+			return x.Int32
+		}
+	}
+	return nil
+}
+
+func (x *FieldRules) GetMoney() *MoneyRules {
+	if x != nil {
+		if x, ok := x.Type.(*FieldRules_Money); ok {
+			return x.Money
+		}
+	}
+	return nil
+}
+
+func (x *FieldRules) GetDuration() *DurationRules {
+	if x != nil {
+		if x, ok := x.Type.(*FieldRules_Duration); ok {
+			return x.Duration
+		}
+	}
+	return nil
+}
+
+func (x *FieldRules) GetRepeated() *RepeatedRules {
+	if x != nil {
+		if x, ok := x.Type.(*FieldRules_Repeated); ok {
+			return x.Repeated
+		}
+	}
+	return nil
+}
+
+type isFieldRules_Type interface {
+	isFieldRules_Type()
+}
+
+type FieldRules_String_ struct {
+	String_ *StringRules `protobuf:"bytes,10,opt,name=string,proto3,oneof"`
+}
+
+type FieldRules_Int32 struct {
+	Int32 *Int32Rules `protobuf:"bytes,11,opt,name=int32,proto3,oneof"`
+}
+
+type FieldRules_Money struct {
+	Money *MoneyRules `protobuf:"bytes,12,opt,name=money,proto3,oneof"`
+}
+
+type FieldRules_Duration struct {
+	Duration *DurationRules `protobuf:"bytes,13,opt,name=duration,proto3,oneof"`
+}
+
+type FieldRules_Repeated struct {
+	Repeated *RepeatedRules `protobuf:"bytes,14,opt,name=repeated,proto3,oneof"`
+}
+
+func (*FieldRules_String_) isFieldRules_Type() {}
+
+func (*FieldRules_Int32) isFieldRules_Type() {}
+
+func (*FieldRules_Money) isFieldRules_Type() {}
+
+func (*FieldRules_Duration) isFieldRules_Type() {}
+
+func (*FieldRules_Repeated) isFieldRules_Type() {}
+
+// stringのルール
+type StringRules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinLen        *uint64                `protobuf:"varint,1,opt,name=min_len,json=minLen,proto3,oneof" json:"min_len,omitempty"` // 最小の文字数（バイト数ではなくUnicodeの文字数）
+	MaxLen        *uint64                `protobuf:"varint,2,opt,name=max_len,json=maxLen,proto3,oneof" json:"max_len,omitempty"` // 最大の文字数
+	Pattern       string                 `protobuf:"bytes,3,opt,name=pattern,proto3" json:"pattern,omitempty"`                    // 一致しなければならない正規表現（RE2）
+	Printable     bool                   `protobuf:"varint,4,opt,name=printable,proto3" json:"printable,omitempty"`               // 制御文字を含まず、前後が空白でないこと
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StringRules) Reset() {
+	*x = StringRules{}
+	mi := &file_proto_validate_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StringRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StringRules) ProtoMessage() {}
+
+func (x *StringRules) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_validate_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StringRules.ProtoReflect.Descriptor instead.
+func (*StringRules) Descriptor() ([]byte, []int) {
+	return file_proto_validate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StringRules) GetMinLen() uint64 {
+	if x != nil && x.MinLen != nil {
+		return *x.MinLen
+	}
+	return 0
+}
+
+func (x *StringRules) GetMaxLen() uint64 {
+	if x != nil && x.MaxLen != nil {
+		return *x.MaxLen
+	}
+	return 0
+}
+
+func (x *StringRules) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *StringRules) GetPrintable() bool {
+	if x != nil {
+		return x.Printable
+	}
+	return false
+}
+
+// int32のルール
+type Int32Rules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Gte           *int32                 `protobuf:"varint,1,opt,name=gte,proto3,oneof" json:"gte,omitempty"` // この値以上
+	Lte           *int32                 `protobuf:"varint,2,opt,name=lte,proto3,oneof" json:"lte,omitempty"` // この値以下
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Int32Rules) Reset() {
+	*x = Int32Rules{}
+	mi := &file_proto_validate_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Int32Rules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Int32Rules) ProtoMessage() {}
+
+func (x *Int32Rules) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_validate_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Int32Rules.ProtoReflect.Descriptor instead.
+func (*Int32Rules) Descriptor() ([]byte, []int) {
+	return file_proto_validate_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Int32Rules) GetGte() int32 {
+	if x != nil && x.Gte != nil {
+		return *x.Gte
+	}
+	return 0
+}
+
+func (x *Int32Rules) GetLte() int32 {
+	if x != nil && x.Lte != nil {
+		return *x.Lte
+	}
+	return 0
+}
+
+// google.type.Moneyのルール
+type MoneyRules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NonNegative   bool                   `protobuf:"varint,1,opt,name=non_negative,json=nonNegative,proto3" json:"non_negative,omitempty"` // 0以上であること（Money自体の形式は常に確認する）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoneyRules) Reset() {
+	*x = MoneyRules{}
+	mi := &file_proto_validate_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoneyRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoneyRules) ProtoMessage() {}
+
+func (x *MoneyRules) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_validate_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoneyRules.ProtoReflect.Descriptor instead.
+func (*MoneyRules) Descriptor() ([]byte, []int) {
+	return file_proto_validate_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MoneyRules) GetNonNegative() bool {
+	if x != nil {
+		return x.NonNegative
+	}
+	return false
+}
+
+// google.protobuf.Durationのルール
+type DurationRules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Positive      bool                   `protobuf:"varint,1,opt,name=positive,proto3" json:"positive,omitempty"` // 0より大きいこと
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DurationRules) Reset() {
+	*x = DurationRules{}
+	mi := &file_proto_validate_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DurationRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DurationRules) ProtoMessage() {}
+
+func (x *DurationRules) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_validate_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DurationRules.ProtoReflect.Descriptor instead.
+func (*DurationRules) Descriptor() ([]byte, []int) {
+	return file_proto_validate_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DurationRules) GetPositive() bool {
+	if x != nil {
+		return x.Positive
+	}
+	return false
+}
+
+// repeatedフィールドのルール
+type RepeatedRules struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxItems      *uint64                `protobuf:"varint,1,opt,name=max_items,json=maxItems,proto3,oneof" json:"max_items,omitempty"` // 最大の要素数
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RepeatedRules) Reset() {
+	*x = RepeatedRules{}
+	mi := &file_proto_validate_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RepeatedRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepeatedRules) ProtoMessage() {}
+
+func (x *RepeatedRules) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_validate_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepeatedRules.ProtoReflect.Descriptor instead.
+func (*RepeatedRules) Descriptor() ([]byte, []int) {
+	return file_proto_validate_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RepeatedRules) GetMaxItems() uint64 {
+	if x != nil && x.MaxItems != nil {
+		return *x.MaxItems
+	}
+	return 0
+}
+
+var file_proto_validate_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldRules)(nil),
+		Field:         51000,
+		Name:          "album.validate.field",
+		Tag:           "bytes,51000,opt,name=field",
+		Filename:      "proto/validate.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional album.validate.FieldRules field = 51000;
+	E_Field = &file_proto_validate_proto_extTypes[0] // 50000〜99999は組織内で自由に使える番号
+)
+
+var File_proto_validate_proto protoreflect.FileDescriptor
+
+const file_proto_validate_proto_rawDesc = "" +
+	"\n" +
+	"\x14proto/validate.proto\x12\x0ealbum.validate\x1a google/protobuf/descriptor.proto\"\xdd\x02\n" +
+	"\n" +
+	"FieldRules\x12\x1a\n" +
+	"\brequired\x18\x01 \x01(\bR\brequired\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\bR\x04skip\x125\n" +
+	"\x06string\x18\n" +
+	" \x01(\v2\x1b.album.validate.StringRulesH\x00R\x06string\x122\n" +
+	"\x05int32\x18\v \x01(\v2\x1a.album.validate.Int32RulesH\x00R\x05int32\x122\n" +
+	"\x05money\x18\f \x01(\v2\x1a.album.validate.MoneyRulesH\x00R\x05money\x12;\n" +
+	"\bduration\x18\r \x01(\v2\x1d.album.validate.DurationRulesH\x00R\bduration\x12;\n" +
+	"\brepeated\x18\x0e \x01(\v2\x1d.album.validate.RepeatedRulesH\x00R\brepeatedB\x06\n" +
+	"\x04type\"\x99\x01\n" +
+	"\vStringRules\x12\x1c\n" +
+	"\amin_len\x18\x01 \x01(\x04H\x00R\x06minLen\x88\x01\x01\x12\x1c\n" +
+	"\amax_len\x18\x02 \x01(\x04H\x01R\x06maxLen\x88\x01\x01\x12\x18\n" +
+	"\apattern\x18\x03 \x01(\tR\apattern\x12\x1c\n" +
+	"\tprintable\x18\x04 \x01(\bR\tprintableB\n" +
+	"\n" +
+	"\b_min_lenB\n" +
+	"\n" +
+	"\b_max_len\"J\n" +
+	"\n" +
+	"Int32Rules\x12\x15\n" +
+	"\x03gte\x18\x01 \x01(\x05H\x00R\x03gte\x88\x01\x01\x12\x15\n" +
+	"\x03lte\x18\x02 \x01(\x05H\x01R\x03lte\x88\x01\x01B\x06\n" +
+	"\x04_gteB\x06\n" +
+	"\x04_lte\"/\n" +
+	"\n" +
+	"MoneyRules\x12!\n" +
+	"\fnon_negative\x18\x01 \x01(\bR\vnonNegative\"+\n" +
+	"\rDurationRules\x12\x1a\n" +
+	"\bpositive\x18\x01 \x01(\bR\bpositive\"?\n" +
+	"\rRepeatedRules\x12 \n" +
+	"\tmax_items\x18\x01 \x01(\x04H\x00R\bmaxItems\x88\x01\x01B\f\n" +
+	"\n" +
+	"_max_items:Q\n" +
+	"\x05field\x12\x1d.google.protobuf.FieldOptions\x18\xb8\x8e\x03 \x01(\v2\x1a.album.validate.FieldRulesR\x05fieldB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_proto_validate_proto_rawDescOnce sync.Once
+	file_proto_validate_proto_rawDescData []byte
+)
+
+func file_proto_validate_proto_rawDescGZIP() []byte {
+	file_proto_validate_proto_rawDescOnce.Do(func() {
+		file_proto_validate_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_validate_proto_rawDesc), len(file_proto_validate_proto_rawDesc)))
+	})
+	return file_proto_validate_proto_rawDescData
+}
+
+var file_proto_validate_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_validate_proto_goTypes = []any{
+	(*FieldRules)(nil),                // 0: album.validate.FieldRules
+	(*StringRules)(nil),               // 1: album.validate.StringRules
+	(*Int32Rules)(nil),                // 2: album.validate.Int32Rules
+	(*MoneyRules)(nil),                // 3: album.validate.MoneyRules
+	(*DurationRules)(nil),             // 4: album.validate.DurationRules
+	(*RepeatedRules)(nil),             // 5: album.validate.RepeatedRules
+	(*descriptorpb.FieldOptions)(nil), // 6: google.protobuf.FieldOptions
+}
+var file_proto_validate_proto_depIdxs = []int32{
+	1, // 0: album.validate.FieldRules.string:type_name -> album.validate.StringRules
+	2, // 1: album.validate.FieldRules.int32:type_name -> album.validate.Int32Rules
+	3, // 2: album.validate.FieldRules.money:type_name -> album.validate.MoneyRules
+	4, // 3: album.validate.FieldRules.duration:type_name -> album.validate.DurationRules
+	5, // 4: album.validate.FieldRules.repeated:type_name -> album.validate.RepeatedRules
+	6, // 5: album.validate.field:extendee -> google.protobuf.FieldOptions
+	0, // 6: album.validate.field:type_name -> album.validate.FieldRules
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	6, // [6:7] is the sub-list for extension type_name
+	5, // [5:6] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_validate_proto_init() }
+func file_proto_validate_proto_init() {
+	if File_proto_validate_proto != nil {
+		return
+	}
+	file_proto_validate_proto_msgTypes[0].OneofWrappers = []any{
+		(*FieldRules_String_)(nil),
+		(*FieldRules_Int32)(nil),
+		(*FieldRules_Money)(nil),
+		(*FieldRules_Duration)(nil),
+		(*FieldRules_Repeated)(nil),
+	}
+	file_proto_validate_proto_msgTypes[1].OneofWrappers = []any{}
+	file_proto_validate_proto_msgTypes[2].OneofWrappers = []any{}
+	file_proto_validate_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_validate_proto_rawDesc), len(file_proto_validate_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_validate_proto_goTypes,
+		DependencyIndexes: file_proto_validate_proto_depIdxs,
+		MessageInfos:      file_proto_validate_proto_msgTypes,
+		ExtensionInfos:    file_proto_validate_proto_extTypes,
+	}.Build()
+	File_proto_validate_proto = out.File
+	file_proto_validate_proto_goTypes = nil
+	file_proto_validate_proto_depIdxs = nil
+}
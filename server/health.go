@@ -0,0 +1,87 @@
+package main
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/store"
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// ストアの状態をヘルスチェックサービスに反映する構造体
+//
+// サービス名""（サーバー全体）とalbum.AlbumServiceの状態を、ストアを定期的に確認した結果で更新する
+// 停止処理が始まった後は、確認の結果に関わらずNOT_SERVINGを返す
+type healthChecker struct {
+	server   *health.Server
+	store    store.AlbumStore
+	interval time.Duration // ストアを確認する間隔
+	timeout  time.Duration // 1回の確認のタイムアウト
+
+	serving bool // 最後に確認したときの状態（状態が変わったときだけログに出す）
+}
+
+// 最初の確認が終わるまではNOT_SERVINGを返すヘルスチェックサービスを作成する関数
+func newHealthChecker(albumStore store.AlbumStore, interval, timeout time.Duration) *healthChecker {
+	h := &healthChecker{
+		server:   health.NewServer(),
+		store:    albumStore,
+		interval: interval,
+		timeout:  timeout,
+	}
+	h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	return h
+}
+
+// ctxが終了するまで、一定の間隔でストアを確認し続けるメソッド
+func (h *healthChecker) run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ストアが読み書きできるか確認し、結果をヘルスチェックサービスに反映するメソッド
+func (h *healthChecker) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	err := h.store.Ping(ctx)
+	if err != nil {
+		if h.serving {
+			slog.Error("album store is not available", "error", err)
+		}
+		h.serving = false
+		h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+
+	if !h.serving {
+		slog.Info("album store is available")
+	}
+	h.serving = true
+	h.setStatus(healthpb.HealthCheckResponse_SERVING)
+}
+
+func (h *healthChecker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.server.SetServingStatus("", status)
+	h.server.SetServingStatus(pb.AlbumService_ServiceDesc.ServiceName, status)
+}
+
+// すべてのサービスをNOT_SERVINGにし、以降の確認結果を反映しないようにするメソッド
+// 停止処理の開始時に呼び、ロードバランサーが新しいリクエストを送らないようにする
+func (h *healthChecker) shutdown() {
+	h.server.Shutdown()
+}
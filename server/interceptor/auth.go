@@ -0,0 +1,106 @@
+package interceptor
+
+import (
+	"awsomeProject/auth"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Unary RPCのBearerトークンを検証し、呼び出し元をコンテキストに設定するインターセプター
+// publicMethodsに含まれるメソッドは認証せずに通す（"/"で終わる要素はサービス全体に一致する）
+func AuthUnaryServerInterceptor(a *auth.Authenticator, publicMethods []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if matchMethod(info.FullMethod, publicMethods) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ストリーム形式のRPCのBearerトークンを検証し、呼び出し元をコンテキストに設定するインターセプター
+// トークンはストリームの開始時に1度だけ検証する
+func AuthStreamServerInterceptor(a *auth.Authenticator, publicMethods []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if matchMethod(info.FullMethod, publicMethods) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), a)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// メタデータのauthorizationからBearerトークンを取り出して検証する関数
+func authenticate(ctx context.Context, a *auth.Authenticator) (context.Context, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := a.Authenticate(token)
+	switch {
+	case errors.Is(err, auth.ErrTokenExpired):
+		return nil, status.Error(codes.Unauthenticated, "token expired")
+	case err != nil:
+		// トークンの推測に使われないよう、詳しい理由はクライアントに返さない
+		slog.InfoContext(ctx, "authentication failed", "error", err)
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	return auth.NewContext(ctx, id), nil
+}
+
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization metadata")
+	}
+
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+		return "", status.Error(codes.Unauthenticated, `authorization metadata must be "Bearer <token>"`)
+	}
+
+	return token, nil
+}
+
+// フルメソッド名がmethodsのいずれかに一致するか判定する関数
+// "/"で終わる要素はサービス内のすべてのメソッドに一致する
+func matchMethod(fullMethod string, methods []string) bool {
+	for _, m := range methods {
+		if m == fullMethod || strings.HasSuffix(m, "/") && strings.HasPrefix(fullMethod, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// コンテキストを差し替えたServerStream
+// インターセプターでコンテキストに設定した値を、ハンドラーのstream.Context()から参照できるようにする
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,85 @@
+package interceptor_test
+
+import (
+	"awsomeProject/auth"
+	"awsomeProject/pb"
+	"awsomeProject/server/interceptor"
+	"awsomeProject/server/interceptor/interceptortest"
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+
+	a, err := auth.NewAuthenticator(auth.Options{
+		APIKeys: []auth.APIKey{{Key: "test-key", Subject: "tester", Roles: []string{"reader"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAuthUnaryServerInterceptor(t *testing.T) {
+	i := interceptor.AuthUnaryServerInterceptor(newTestAuthenticator(t), []string{"/grpc.health.v1.Health/"})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "missing token", ctx: context.Background(), method: "/album.AlbumService/GetAlbum", wantCode: codes.Unauthenticated},
+		{name: "unknown key", ctx: withToken("wrong-key"), method: "/album.AlbumService/GetAlbum", wantCode: codes.Unauthenticated},
+		{name: "valid key", ctx: withToken("test-key"), method: "/album.AlbumService/GetAlbum", wantCode: codes.OK},
+		{name: "public method", ctx: context.Background(), method: "/grpc.health.v1.Health/Check", wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := interceptortest.RunUnary(tt.ctx, i, tt.method, &pb.GetAlbumRequest{Id: "1"}, nil)
+			if code := status.Code(result.Err); code != tt.wantCode {
+				t.Fatalf("code = %s, want %s", code, tt.wantCode)
+			}
+			if result.Called != (tt.wantCode == codes.OK) {
+				t.Errorf("handler called = %v", result.Called)
+			}
+		})
+	}
+
+	t.Run("identity in context", func(t *testing.T) {
+		result := interceptortest.RunUnary(withToken("test-key"), i, "/album.AlbumService/GetAlbum", &pb.GetAlbumRequest{Id: "1"}, nil)
+		id, ok := auth.FromContext(result.Context)
+		if !ok || id.Subject != "tester" {
+			t.Errorf("identity = %v, want subject tester", id)
+		}
+	})
+}
+
+func TestAuthStreamServerInterceptor(t *testing.T) {
+	i := interceptor.AuthStreamServerInterceptor(newTestAuthenticator(t), nil)
+
+	ss := interceptortest.NewServerStream(context.Background(), "/album.AlbumService/UploadAndNotify", &pb.UploadAndNotifyRequest{})
+	if err := interceptortest.RunStream(i, ss, nil); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("code = %s, want Unauthenticated", status.Code(err))
+	}
+	if ss.Called() {
+		t.Error("handler was called without a token")
+	}
+
+	ss = interceptortest.NewServerStream(withToken("test-key"), "/album.AlbumService/UploadAndNotify", &pb.UploadAndNotifyRequest{})
+	if err := interceptortest.RunStream(i, ss, nil); err != nil {
+		t.Fatalf("RunStream() error = %v", err)
+	}
+	if len(ss.Sent()) != 1 {
+		t.Errorf("sent %d messages, want 1", len(ss.Sent()))
+	}
+}
@@ -0,0 +1,53 @@
+package interceptor
+
+import (
+	"awsomeProject/auth"
+	"context"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 呼び出し元のロールがポリシーでUnary RPCのメソッドに許可されているか確認するインターセプター
+// 認証のインターセプターの後に設定し、認証をしないpublicMethodsには同じ一覧を渡す
+func AuthzUnaryServerInterceptor(policy *auth.Policy, publicMethods []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !matchMethod(info.FullMethod, publicMethods) {
+			if err := authorize(ctx, policy, info.FullMethod); err != nil {
+				return nil, err
+			}
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// 呼び出し元のロールがポリシーでストリーム形式のRPCのメソッドに許可されているか確認するインターセプター
+func AuthzStreamServerInterceptor(policy *auth.Policy, publicMethods []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !matchMethod(info.FullMethod, publicMethods) {
+			if err := authorize(ss.Context(), policy, info.FullMethod); err != nil {
+				return err
+			}
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func authorize(ctx context.Context, policy *auth.Policy, fullMethod string) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		// 認証のインターセプターが設定されていない
+		return status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+
+	if err := policy.Authorize(fullMethod, id); err != nil {
+		slog.InfoContext(ctx, "authorization failed", "subject", id.Subject, "error", err)
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", id.Subject, fullMethod)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+)
+
+// チェーンに追加するインターセプターの名前
+// 設定ファイルでメソッドごとに有効・無効を指定するときにも使う
+const (
+	NameRequestID = "request_id"
+	NameTracing   = "tracing"
+	NameLogging   = "logging"
+	NameMetrics   = "metrics"
+	NameAuth      = "auth"
+	NameRateLimit = "rate_limit"
+	NameAuthz     = "authz"
+	NameValidate  = "validate"
+	NameRecovery  = "recovery"
+)
+
+// チェーン内での実行順（先頭ほど外側で、先に実行される）
+// 設定ファイルの名前を確認するconfig.InterceptorNamesも同じ順に並べる
+//
+// リクエストIDとトレースは後続のログに付けるため最初に、ログとメトリクスは認証や検証で拒否された呼び出しも
+// 記録するため認証より前に置く。panicの回復は、認証や頻度制限などのインターセプターのpanicも回復して
+// ログとトレースにInternalのエラーとして記録できるよう、ログの直後に置く（メトリクスではpanicの数を別に数える）。
+// 頻度制限は認証された名前で区別するため認証の後、検証は認可を通った呼び出しだけに行う。
+var chainOrder = []string{
+	NameRequestID,
+	NameTracing,
+	NameLogging,
+	NameRecovery,
+	NameMetrics,
+	NameAuth,
+	NameRateLimit,
+	NameAuthz,
+	NameValidate,
+}
+
+// インターセプターを実行するメソッドの条件
+// メソッドは"/album.AlbumService/GetAlbum"のようなフルメソッド名か、"/album.AlbumService/"のように"/"で終わるサービス名で指定する
+type MethodFilter struct {
+	Include []string // 空でなければ、一致するメソッドでだけ実行する
+	Exclude []string // 一致するメソッドでは実行しない（Includeより優先する）
+}
+
+// メソッドでインターセプターを実行するか判定するメソッド
+func (f MethodFilter) Match(fullMethod string) bool {
+	if len(f.Include) > 0 && !matchMethod(fullMethod, f.Include) {
+		return false
+	}
+
+	return !matchMethod(fullMethod, f.Exclude)
+}
+
+// インターセプターを決められた順に組み立てるビルダー
+// 追加した順番にかかわらず、chainOrderの順に実行される
+type Chain struct {
+	unary   map[string]grpc.UnaryServerInterceptor
+	stream  map[string]grpc.StreamServerInterceptor
+	filters map[string]MethodFilter
+}
+
+func NewChain() *Chain {
+	return &Chain{
+		unary:   make(map[string]grpc.UnaryServerInterceptor),
+		stream:  make(map[string]grpc.StreamServerInterceptor),
+		filters: make(map[string]MethodFilter),
+	}
+}
+
+// 名前に対応する位置にインターセプターを追加するメソッド
+// Unary RPCかストリーム形式のRPCの一方にしか使わない場合は、もう一方にnilを渡す
+// 名前が不明な場合や、同じ名前を2回追加した場合はプログラムの誤りのためpanicする
+func (c *Chain) Add(name string, unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) {
+	if !slices.Contains(chainOrder, name) {
+		panic("interceptor: unknown interceptor name " + name)
+	}
+	if c.unary[name] != nil || c.stream[name] != nil {
+		panic("interceptor: interceptor added twice: " + name)
+	}
+
+	if unary != nil {
+		c.unary[name] = unary
+	}
+	if stream != nil {
+		c.stream[name] = stream
+	}
+}
+
+// 名前で指定したインターセプターを実行するメソッドを絞り込むメソッド
+// 設定ファイルの値を渡すため、名前が不明な場合はエラーを返す
+func (c *Chain) SetFilter(name string, filter MethodFilter) error {
+	if !slices.Contains(chainOrder, name) {
+		return fmt.Errorf("unknown interceptor %q", name)
+	}
+
+	c.filters[name] = filter
+	return nil
+}
+
+// 追加されたUnary RPCのインターセプターを実行順に返すメソッド
+func (c *Chain) Unary() []grpc.UnaryServerInterceptor {
+	var interceptors []grpc.UnaryServerInterceptor
+	for _, name := range chainOrder {
+		if i, ok := c.unary[name]; ok {
+			interceptors = append(interceptors, c.filterUnary(name, i))
+		}
+	}
+
+	return interceptors
+}
+
+// 追加されたストリーム形式のRPCのインターセプターを実行順に返すメソッド
+func (c *Chain) Stream() []grpc.StreamServerInterceptor {
+	var interceptors []grpc.StreamServerInterceptor
+	for _, name := range chainOrder {
+		if i, ok := c.stream[name]; ok {
+			interceptors = append(interceptors, c.filterStream(name, i))
+		}
+	}
+
+	return interceptors
+}
+
+// チェーンをgRPCサーバーに設定するオプションを返すメソッド
+func (c *Chain) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(c.Unary()...),
+		grpc.ChainStreamInterceptor(c.Stream()...),
+	}
+}
+
+// チェーン全体を1つのUnary RPCのインターセプターにまとめるメソッド
+// gRPCサーバーを使わずにチェーンを実行したい場合（interceptortestなど）に使う
+func (c *Chain) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	interceptors := c.Unary()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// チェーン全体を1つのストリーム形式のRPCのインターセプターにまとめるメソッド
+func (c *Chain) StreamInterceptor() grpc.StreamServerInterceptor {
+	interceptors := c.Stream()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, inner)
+			}
+		}
+
+		return next(srv, ss)
+	}
+}
+
+// 絞り込みの条件に一致しないメソッドでは、インターセプターを飛ばして次を呼ぶようにする
+func (c *Chain) filterUnary(name string, i grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	filter, ok := c.filters[name]
+	if !ok {
+		return i
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !filter.Match(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		return i(ctx, req, info, handler)
+	}
+}
+
+func (c *Chain) filterStream(name string, i grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	filter, ok := c.filters[name]
+	if !ok {
+		return i
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !filter.Match(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		return i(srv, ss, info, handler)
+	}
+}
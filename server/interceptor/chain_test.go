@@ -0,0 +1,251 @@
+package interceptor_test
+
+import (
+	"awsomeProject/config"
+	"awsomeProject/pb"
+	"awsomeProject/server/interceptor"
+	"awsomeProject/server/interceptor/interceptortest"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 実行されたインターセプターの名前を順に記録する
+type recorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *recorder) record(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.names = append(r.names, name)
+}
+
+func (r *recorder) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := r.names
+	r.names = nil
+	return names
+}
+
+func (r *recorder) unary(name string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r.record(name)
+		return handler(ctx, req)
+	}
+}
+
+func (r *recorder) stream(name string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		r.record(name)
+		return handler(srv, ss)
+	}
+}
+
+// 実行順に並べたインターセプターの名前
+var allNames = []string{
+	interceptor.NameRequestID,
+	interceptor.NameTracing,
+	interceptor.NameLogging,
+	interceptor.NameRecovery,
+	interceptor.NameMetrics,
+	interceptor.NameAuth,
+	interceptor.NameRateLimit,
+	interceptor.NameAuthz,
+	interceptor.NameValidate,
+}
+
+// すべてのインターセプターを、実行順とは異なる順に追加したチェーンを作成する関数
+func newRecordingChain(r *recorder) *interceptor.Chain {
+	chain := interceptor.NewChain()
+	added := slices.Clone(allNames)
+	slices.Reverse(added)
+	added[0], added[4] = added[4], added[0]
+	for _, name := range added {
+		chain.Add(name, r.unary(name), r.stream(name))
+	}
+
+	return chain
+}
+
+func TestChainOrder(t *testing.T) {
+	r := &recorder{}
+	chain := newRecordingChain(r)
+
+	result := interceptortest.RunUnary(context.Background(), chain.UnaryInterceptor(), "/album.AlbumService/GetAlbum", &pb.GetAlbumRequest{Id: "1"}, nil)
+	if result.Err != nil || !result.Called {
+		t.Fatalf("RunUnary() err = %v, called = %v", result.Err, result.Called)
+	}
+	if got := r.take(); !slices.Equal(got, allNames) {
+		t.Errorf("unary order = %v, want %v", got, allNames)
+	}
+
+	ss := interceptortest.NewServerStream(context.Background(), "/album.AlbumService/UploadAndNotify", &pb.UploadAndNotifyRequest{})
+	if err := interceptortest.RunStream(chain.StreamInterceptor(), ss, nil); err != nil {
+		t.Fatalf("RunStream() error = %v", err)
+	}
+	if got := r.take(); !slices.Equal(got, allNames) {
+		t.Errorf("stream order = %v, want %v", got, allNames)
+	}
+}
+
+// 設定ファイルで指定できる名前は、チェーンに追加できる名前と一致していなければならない
+func TestChainNamesMatchConfig(t *testing.T) {
+	if !slices.Equal(config.InterceptorNames, allNames) {
+		t.Errorf("config.InterceptorNames = %v, want %v", config.InterceptorNames, allNames)
+	}
+	for _, name := range config.InterceptorNames {
+		if err := interceptor.NewChain().SetFilter(name, interceptor.MethodFilter{}); err != nil {
+			t.Errorf("SetFilter(%q) error = %v", name, err)
+		}
+	}
+}
+
+// 回復より内側のインターセプターのpanicも回復し、外側のログにはInternalのエラーとして見せる
+func TestChainRecoversInterceptorPanic(t *testing.T) {
+	var logged []codes.Code
+	chain := interceptor.NewChain()
+	chain.Add(interceptor.NameLogging,
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			resp, err := handler(ctx, req)
+			logged = append(logged, status.Code(err))
+			return resp, err
+		},
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			err := handler(srv, ss)
+			logged = append(logged, status.Code(err))
+			return err
+		})
+	chain.Add(interceptor.NameRecovery, interceptor.RecoveryUnaryServerInterceptor(nil), interceptor.RecoveryStreamServerInterceptor(nil))
+	chain.Add(interceptor.NameAuth,
+		func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			panic("auth bug")
+		},
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			panic("auth bug")
+		})
+
+	result := interceptortest.RunUnary(context.Background(), chain.UnaryInterceptor(), "/album.AlbumService/GetAlbum", &pb.GetAlbumRequest{Id: "1"}, nil)
+	if result.Called {
+		t.Error("handler was called after the interceptor panicked")
+	}
+	if code := status.Code(result.Err); code != codes.Internal {
+		t.Errorf("unary code = %s, want Internal", code)
+	}
+
+	ss := interceptortest.NewServerStream(context.Background(), "/album.AlbumService/UploadAndNotify", &pb.UploadAndNotifyRequest{})
+	if code := status.Code(interceptortest.RunStream(chain.StreamInterceptor(), ss, nil)); code != codes.Internal {
+		t.Errorf("stream code = %s, want Internal", code)
+	}
+
+	if want := []codes.Code{codes.Internal, codes.Internal}; !slices.Equal(logged, want) {
+		t.Errorf("logged codes = %v, want %v", logged, want)
+	}
+}
+
+func TestChainFilter(t *testing.T) {
+	r := &recorder{}
+	chain := newRecordingChain(r)
+	if err := chain.SetFilter(interceptor.NameLogging, interceptor.MethodFilter{
+		Include: []string{"/album.AlbumService/"},
+		Exclude: []string{"/album.AlbumService/DeleteAlbum"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.SetFilter(interceptor.NameAuth, interceptor.MethodFilter{Exclude: []string{"/grpc.health.v1.Health/"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	without := func(names ...string) []string {
+		return slices.DeleteFunc(slices.Clone(allNames), func(name string) bool { return slices.Contains(names, name) })
+	}
+	tests := []struct {
+		method string
+		want   []string
+	}{
+		{method: "/album.AlbumService/GetAlbum", want: allNames},
+		{method: "/album.AlbumService/DeleteAlbum", want: without(interceptor.NameLogging)}, // ExcludeはIncludeより優先する
+		{method: "/grpc.health.v1.Health/Check", want: without(interceptor.NameLogging, interceptor.NameAuth)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			result := interceptortest.RunUnary(context.Background(), chain.UnaryInterceptor(), tt.method, &pb.GetAlbumRequest{}, nil)
+			if !result.Called {
+				t.Fatal("handler was not called")
+			}
+			if got := r.take(); !slices.Equal(got, tt.want) {
+				t.Errorf("executed = %v, want %v", got, tt.want)
+			}
+
+			ss := interceptortest.NewServerStream(context.Background(), tt.method)
+			if err := interceptortest.RunStream(chain.StreamInterceptor(), ss, nil); err != nil {
+				t.Fatal(err)
+			}
+			if got := r.take(); !slices.Equal(got, tt.want) {
+				t.Errorf("stream executed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMethodFilterMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter interceptor.MethodFilter
+		method string
+		want   bool
+	}{
+		{name: "empty filter", method: "/album.AlbumService/GetAlbum", want: true},
+		{name: "included method", filter: interceptor.MethodFilter{Include: []string{"/album.AlbumService/GetAlbum"}}, method: "/album.AlbumService/GetAlbum", want: true},
+		{name: "not included", filter: interceptor.MethodFilter{Include: []string{"/album.AlbumService/GetAlbum"}}, method: "/album.AlbumService/ListAlbums", want: false},
+		{name: "included service", filter: interceptor.MethodFilter{Include: []string{"/album.AlbumService/"}}, method: "/album.AlbumService/ListAlbums", want: true},
+		{name: "excluded service", filter: interceptor.MethodFilter{Exclude: []string{"/grpc.health.v1.Health/"}}, method: "/grpc.health.v1.Health/Watch", want: false},
+		{name: "exclude wins over include", filter: interceptor.MethodFilter{Include: []string{"/album.AlbumService/"}, Exclude: []string{"/album.AlbumService/DeleteAlbum"}}, method: "/album.AlbumService/DeleteAlbum", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(tt.method); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChainSetFilterUnknownName(t *testing.T) {
+	if err := interceptor.NewChain().SetFilter("unknown", interceptor.MethodFilter{}); err == nil {
+		t.Error("SetFilter() error = nil, want error for unknown interceptor")
+	}
+}
+
+func TestChainAddPanics(t *testing.T) {
+	r := &recorder{}
+	tests := []struct {
+		name string
+		add  func(*interceptor.Chain)
+	}{
+		{name: "unknown name", add: func(c *interceptor.Chain) { c.Add("unknown", r.unary("unknown"), nil) }},
+		{name: "added twice", add: func(c *interceptor.Chain) {
+			c.Add(interceptor.NameLogging, r.unary(interceptor.NameLogging), nil)
+			c.Add(interceptor.NameLogging, nil, r.stream(interceptor.NameLogging))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Add did not panic")
+				}
+			}()
+			tt.add(interceptor.NewChain())
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// gRPCサーバーを起動せずに、インターセプターを偽のハンドラーに対して実行するためのパッケージ
+//
+// インターセプターに渡すコンテキストには偽のServerTransportStreamを設定するため、
+// grpc.Methodやgrpc.SetHeader・grpc.SetTrailerを使うインターセプターもそのまま実行できる
+package interceptortest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// Unary RPCのインターセプターを実行した結果
+type UnaryResult struct {
+	Response interface{}
+	Err      error
+	Called   bool            // ハンドラーが呼ばれたか
+	Context  context.Context // ハンドラーに渡されたコンテキスト（呼ばれなかった場合はnil）
+	Header   metadata.MD     // インターセプターやハンドラーが設定したヘッダー
+	Trailer  metadata.MD     // インターセプターやハンドラーが設定したトレーラー
+}
+
+// Unary RPCのインターセプターを、fullMethodの呼び出しとしてhandlerに対して実行する関数
+// handlerがnilの場合は、リクエストをそのままレスポンスとして返すハンドラーを使う
+func RunUnary(ctx context.Context, i grpc.UnaryServerInterceptor, fullMethod string, req interface{}, handler grpc.UnaryHandler) *UnaryResult {
+	if handler == nil {
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return req, nil
+		}
+	}
+
+	ts := &transportStream{method: fullMethod}
+	ctx = grpc.NewContextWithServerTransportStream(withPeer(ctx), ts)
+
+	result := &UnaryResult{}
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	result.Response, result.Err = i(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		result.Called = true
+		result.Context = ctx
+		return handler(ctx, req)
+	})
+	result.Header, result.Trailer = ts.metadata()
+
+	return result
+}
+
+// ストリーム形式のRPCのインターセプターを、ssに対する呼び出しとしてhandlerに対して実行する関数
+// handlerがnilの場合は、DrainHandlerでメッセージをすべて受信してそのまま送り返す
+func RunStream(i grpc.StreamServerInterceptor, ss *ServerStream, handler grpc.StreamHandler) error {
+	if handler == nil {
+		handler = DrainHandler(nil)
+	}
+
+	info := &grpc.StreamServerInfo{
+		FullMethod:     ss.method,
+		IsClientStream: ss.IsClientStream,
+		IsServerStream: ss.IsServerStream,
+	}
+	return i(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		ss.mu.Lock()
+		ss.called = true
+		ss.mu.Unlock()
+		return handler(srv, stream)
+	})
+}
+
+// ストリームが終わるまでメッセージを受信し、受信したメッセージをそのまま送り返すハンドラーを返す関数
+// newMessageには受信するメッセージの型の空の値を返す関数を渡す（nilの場合は送られる予定のメッセージと同じ型を使う）
+func DrainHandler(newMessage func() proto.Message) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		ss, _ := stream.Context().Value(serverStreamKey{}).(*ServerStream)
+		for {
+			var m proto.Message
+			switch {
+			case newMessage != nil:
+				m = newMessage()
+			case ss != nil:
+				next, ok := ss.peek()
+				if !ok {
+					return nil
+				}
+				m = next.ProtoReflect().New().Interface()
+			default:
+				return errors.New("interceptortest: newMessage is required for streams not created by NewServerStream")
+			}
+
+			err := stream.RecvMsg(m)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := stream.SendMsg(m); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// 決められたメッセージを順に受信し、送信したメッセージを記録する偽のServerStream
+type ServerStream struct {
+	IsClientStream bool // クライアントが複数のメッセージを送るRPCとして実行する
+	IsServerStream bool // サーバーが複数のメッセージを送るRPCとして実行する
+
+	ctx    context.Context
+	method string
+	ts     *transportStream
+
+	mu     sync.Mutex
+	recv   []proto.Message
+	sent   []interface{}
+	called bool
+}
+
+// fullMethodの呼び出しとして、recvを順に受信するServerStreamを作成する関数
+// デフォルトでは双方向ストリーミングのRPCとして扱う
+func NewServerStream(ctx context.Context, fullMethod string, recv ...proto.Message) *ServerStream {
+	ts := &transportStream{method: fullMethod}
+	ss := &ServerStream{
+		IsClientStream: true,
+		IsServerStream: true,
+		method:         fullMethod,
+		ts:             ts,
+		recv:           recv,
+	}
+	ss.ctx = context.WithValue(grpc.NewContextWithServerTransportStream(withPeer(ctx), ts), serverStreamKey{}, ss)
+
+	return ss
+}
+
+func (s *ServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// 次のメッセージをmにコピーし、受信するメッセージがなくなった場合はio.EOFを返す
+func (s *ServerStream) RecvMsg(m interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.recv) == 0 {
+		return io.EOF
+	}
+	next := s.recv[0]
+	s.recv = s.recv[1:]
+
+	dst, ok := m.(proto.Message)
+	if !ok {
+		return errors.New("interceptortest: RecvMsg requires a proto.Message")
+	}
+	proto.Reset(dst)
+	proto.Merge(dst, next)
+
+	return nil
+}
+
+func (s *ServerStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pm, ok := m.(proto.Message); ok {
+		m = proto.Clone(pm) // 送信後に呼び出し元が書き換えても記録が変わらないようにする
+	}
+	s.sent = append(s.sent, m)
+
+	return nil
+}
+
+func (s *ServerStream) SetHeader(md metadata.MD) error {
+	return s.ts.SetHeader(md)
+}
+
+func (s *ServerStream) SendHeader(md metadata.MD) error {
+	return s.ts.SendHeader(md)
+}
+
+func (s *ServerStream) SetTrailer(md metadata.MD) {
+	s.ts.SetTrailer(md)
+}
+
+// 送信されたメッセージを送信順に返すメソッド
+func (s *ServerStream) Sent() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]interface{}(nil), s.sent...)
+}
+
+// まだ受信されていないメッセージの数を返すメソッド
+func (s *ServerStream) Remaining() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.recv)
+}
+
+// ハンドラーが呼ばれたか返すメソッド
+func (s *ServerStream) Called() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.called
+}
+
+// インターセプターやハンドラーが設定したヘッダーを返すメソッド
+func (s *ServerStream) Header() metadata.MD {
+	header, _ := s.ts.metadata()
+	return header
+}
+
+// インターセプターやハンドラーが設定したトレーラーを返すメソッド
+func (s *ServerStream) Trailer() metadata.MD {
+	_, trailer := s.ts.metadata()
+	return trailer
+}
+
+func (s *ServerStream) peek() (proto.Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.recv) == 0 {
+		return nil, false
+	}
+	return s.recv[0], true
+}
+
+type serverStreamKey struct{}
+
+// grpc.Methodやgrpc.SetHeaderが参照する偽のServerTransportStream
+type transportStream struct {
+	method string
+
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (t *transportStream) Method() string {
+	return t.method
+}
+
+func (t *transportStream) SetHeader(md metadata.MD) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.header = metadata.Join(t.header, md)
+	return nil
+}
+
+func (t *transportStream) SendHeader(md metadata.MD) error {
+	return t.SetHeader(md)
+}
+
+func (t *transportStream) SetTrailer(md metadata.MD) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trailer = metadata.Join(t.trailer, md)
+	return nil
+}
+
+func (t *transportStream) metadata() (metadata.MD, metadata.MD) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.header.Copy(), t.trailer.Copy()
+}
+
+// 接続元を参照するインターセプター（ログや頻度制限）のため、接続元が設定されていなければ設定する
+func withPeer(ctx context.Context) context.Context {
+	if _, ok := peer.FromContext(ctx); ok {
+		return ctx
+	}
+
+	return peer.NewContext(ctx, &peer.Peer{Addr: fakeAddr{}})
+}
+
+type fakeAddr struct{}
+
+func (fakeAddr) Network() string { return "tcp" }
+func (fakeAddr) String() string  { return "192.0.2.1:12345" }
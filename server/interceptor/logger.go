@@ -1,7 +1,59 @@
 package interceptor
 
-import "fmt"
+import (
+	"context"
+	"io"
+	"log/slog"
 
-func Logger(format string, args ...interface{}) {
-	fmt.Printf("[server] "+format+"\n", args...)
-}
\ No newline at end of file
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// ログの出力形式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// 指定したレベルと形式でログを出力するLoggerを作成する関数
+// コンテキストを渡して出力したログには、リクエストIDと呼び出されたメソッド、トレースIDが付く
+func NewLogger(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	if format == FormatJSON {
+		h = slog.NewJSONHandler(w, opts)
+	} else {
+		h = slog.NewTextHandler(w, opts)
+	}
+
+	return slog.New(&contextHandler{h})
+}
+
+// コンテキストに含まれるRPCの情報をログに追加するslog.Handler
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if method, ok := grpc.Method(ctx); ok {
+		r.AddAttrs(slog.String("method", method))
+	}
+	if id, ok := RequestIDFromContext(ctx); ok {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	// トレースと突き合わせられるよう、記録中のスパンがあればIDを付ける
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
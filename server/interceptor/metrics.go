@@ -0,0 +1,140 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RPCのメトリクス
+// ラベルのgrpc_typeはunary、client_stream、server_stream、bidi_streamのいずれか
+type Metrics struct {
+	started  *prometheus.CounterVec   // 開始したRPCの数
+	handled  *prometheus.CounterVec   // 終了したRPCの数（ステータスコード別）
+	duration *prometheus.HistogramVec // RPCの処理時間
+	received *prometheus.CounterVec   // 受信したメッセージの数
+	sent     *prometheus.CounterVec   // 送信したメッセージの数
+	panics   *prometheus.CounterVec   // ハンドラーで発生して回復したpanicの数
+}
+
+// RPCのメトリクスを作成し、regに登録する関数
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+
+	m := &Metrics{
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, labels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(labels, "grpc_code")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken by the server to handle RPCs.",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		}, labels),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of messages received by the server.",
+		}, labels),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of messages sent by the server.",
+		}, labels),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_panics_recovered_total",
+			Help: "Total number of panics recovered in RPC handlers.",
+		}, labels),
+	}
+	reg.MustRegister(m.started, m.handled, m.duration, m.received, m.sent, m.panics)
+
+	return m
+}
+
+// 登録されているすべてのメソッドのメトリクスを0で初期化するメソッド
+// 一度も呼ばれていないメソッドも/metricsに出力されるようにする
+func (m *Metrics) InitializeMetrics(server *grpc.Server) {
+	for service, info := range server.GetServiceInfo() {
+		for _, method := range info.Methods {
+			kind := rpcKind(method.IsClientStream, method.IsServerStream)
+			m.started.WithLabelValues(kind, service, method.Name)
+			m.duration.WithLabelValues(kind, service, method.Name)
+			m.received.WithLabelValues(kind, service, method.Name)
+			m.sent.WithLabelValues(kind, service, method.Name)
+			m.panics.WithLabelValues(kind, service, method.Name)
+			m.handled.WithLabelValues(kind, service, method.Name, codes.OK.String())
+		}
+	}
+}
+
+// Unary RPCのメトリクスを記録するインターセプター
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		service, method := splitMethod(info.FullMethod)
+		labels := []string{"unary", service, method}
+
+		start := time.Now()
+		m.started.WithLabelValues(labels...).Inc()
+		m.received.WithLabelValues(labels...).Inc()
+
+		resp, err := handler(ctx, req)
+
+		if err == nil {
+			m.sent.WithLabelValues(labels...).Inc()
+		}
+		m.finish(labels, start, err)
+
+		return resp, err
+	}
+}
+
+// ストリーム形式のRPCのメトリクスを記録するインターセプター
+// メッセージの数はwrappedServerStreamの送受信のたびに数える
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		service, method := splitMethod(info.FullMethod)
+		labels := []string{streamKind(info), service, method}
+
+		start := time.Now()
+		m.started.WithLabelValues(labels...).Inc()
+
+		received := m.received.WithLabelValues(labels...)
+		sent := m.sent.WithLabelValues(labels...)
+		ws := newWrappedServerStream(ss)
+		ws.onRecv = func(interface{}) { received.Inc() }
+		ws.onSend = func(interface{}) { sent.Inc() }
+
+		err := handler(srv, ws)
+
+		m.finish(labels, start, err)
+
+		return err
+	}
+}
+
+func (m *Metrics) finish(labels []string, start time.Time, err error) {
+	m.handled.WithLabelValues(append(labels, status.Code(err).String())...).Inc()
+	m.duration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) panicRecovered(kind, fullMethod string) {
+	service, method := splitMethod(fullMethod)
+	m.panics.WithLabelValues(kind, service, method).Inc()
+}
+
+// "/album.AlbumService/GetAlbum"をサービス名とメソッド名に分ける関数
+func splitMethod(fullMethod string) (service, method string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", "unknown"
+	}
+
+	return service, method
+}
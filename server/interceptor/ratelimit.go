@@ -0,0 +1,203 @@
+package interceptor
+
+import (
+	"awsomeProject/auth"
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// 制限を超えたときに、再試行できるまでの秒数を返すメタデータのキー
+const RetryAfterKey = "retry-after"
+
+// 使われなくなったトークンバケットを削除するまでの時間
+const bucketIdleTTL = 10 * time.Minute
+
+// トークンバケットの設定
+// PerSecondが0以下の場合は制限しない
+type RateLimit struct {
+	PerSecond float64 // 1秒あたりに補充するトークンの数
+	Burst     int     // バケットに貯められるトークンの最大数
+}
+
+// 呼び出し元ごとのトークンバケットでRPCの頻度を制限する構造体
+//
+// 呼び出し元は、認証されていれば認証された名前、そうでなければ接続元のIPアドレスで区別する
+// Unary RPCの呼び出しと、ストリームの開始およびストリーム内で受信するメッセージは別々に制限する
+type RateLimiter struct {
+	unary  *buckets
+	stream *buckets
+}
+
+// Unary RPCの呼び出しと、ストリームの開始および受信するメッセージの制限を指定してRateLimiterを作成する関数
+func NewRateLimiter(unary, streamMessages RateLimit) *RateLimiter {
+	return &RateLimiter{
+		unary:  newBuckets(unary),
+		stream: newBuckets(streamMessages),
+	}
+}
+
+// Unary RPCの呼び出しの頻度を制限するインターセプター
+// 認証された名前で区別するため、認証のインターセプターの後に設定する
+func (l *RateLimiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if delay, ok := l.unary.allow(callerKey(ctx)); !ok {
+			grpc.SetTrailer(ctx, retryAfter(delay))
+			return nil, rateLimitError(ctx, "calls", delay)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ストリームの開始と、ストリーム内で受信するメッセージの頻度を制限するインターセプター
+// 開始時にもトークンを1つ取り出すため、メッセージを受信しないサーバーストリーミングの呼び出しも制限される
+// メッセージの制限はwrappedServerStreamのRecvMsgで確認し、超えた場合はRecvMsgがエラーを返す
+func (l *RateLimiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		key := callerKey(ss.Context())
+
+		if delay, ok := l.stream.allow(key); !ok {
+			ss.SetTrailer(retryAfter(delay))
+			return rateLimitError(ss.Context(), "streams", delay)
+		}
+
+		ws := newWrappedServerStream(ss)
+		ws.checkRecv = func(interface{}) error {
+			if delay, ok := l.stream.allow(key); !ok {
+				ss.SetTrailer(retryAfter(delay))
+				return rateLimitError(ss.Context(), "stream messages", delay)
+			}
+			return nil
+		}
+
+		return handler(srv, ws)
+	}
+}
+
+// 呼び出し元を区別するキーを返す関数
+func callerKey(ctx context.Context) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		return "subject:" + id.Subject
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	// 同じクライアントの別の接続も同じバケットを使うよう、ポート番号は除く
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return "peer:" + p.Addr.String()
+	}
+
+	return "peer:" + host
+}
+
+func retryAfter(delay time.Duration) metadata.MD {
+	seconds := int(math.Ceil(delay.Seconds()))
+	return metadata.Pairs(RetryAfterKey, strconv.Itoa(max(seconds, 1)))
+}
+
+func rateLimitError(ctx context.Context, kind string, delay time.Duration) error {
+	slog.InfoContext(ctx, "rate limit exceeded", "kind", kind, "caller", callerKey(ctx), "retry_after", delay)
+
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("rate limit exceeded for %s, retry after %s", kind, delay.Round(time.Millisecond)))
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// 呼び出し元ごとのトークンバケット
+type buckets struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newBuckets(cfg RateLimit) *buckets {
+	b := &buckets{
+		limit:   rate.Limit(cfg.PerSecond),
+		burst:   cfg.Burst,
+		buckets: make(map[string]*bucket),
+	}
+	if cfg.PerSecond <= 0 {
+		b.limit = rate.Inf
+	}
+	// Burstが0だと1つも通らないため、最低でも1つは貯められるようにする
+	if b.burst < 1 {
+		b.burst = 1
+	}
+
+	return b
+}
+
+// keyのバケットからトークンを1つ取り出すメソッド
+// トークンがない場合はfalseと、次のトークンが補充されるまでの時間を返す
+func (b *buckets) allow(key string) (time.Duration, bool) {
+	if b.limit == rate.Inf {
+		return 0, true
+	}
+
+	now := time.Now()
+
+	b.mu.Lock()
+	b.sweep(now)
+	bk, ok := b.buckets[key]
+	if !ok {
+		bk = &bucket{limiter: rate.NewLimiter(b.limit, b.burst)}
+		b.buckets[key] = bk
+	}
+	bk.lastSeen = now
+	b.mu.Unlock()
+
+	if bk.limiter.AllowN(now, 1) {
+		return 0, true
+	}
+
+	// 予約して待ち時間だけ確認し、トークンは消費しない
+	r := bk.limiter.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	r.CancelAt(now)
+
+	return delay, false
+}
+
+// 一定時間使われていないバケットを削除するメソッド（呼び出し側がmuのロックを取っていること）
+func (b *buckets) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < bucketIdleTTL {
+		return
+	}
+	b.lastSweep = now
+
+	for key, bk := range b.buckets {
+		if now.Sub(bk.lastSeen) > bucketIdleTTL {
+			delete(b.buckets, key)
+		}
+	}
+}
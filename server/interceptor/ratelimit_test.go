@@ -0,0 +1,86 @@
+package interceptor_test
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/interceptor"
+	"awsomeProject/server/interceptor/interceptortest"
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// エラーがResourceExhaustedで、再試行までの時間がトレーラーと詳細の両方に設定されていることを確認する関数
+func assertRateLimited(t *testing.T, err error, trailer metadata.MD) {
+	t.Helper()
+
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %s, want ResourceExhausted", st.Code())
+	}
+	if values := trailer.Get(interceptor.RetryAfterKey); len(values) != 1 || values[0] == "" || values[0] == "0" {
+		t.Errorf("retry-after trailer = %v", values)
+	}
+
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = d
+		}
+	}
+	if retryInfo == nil || retryInfo.GetRetryDelay().AsDuration() <= 0 {
+		t.Errorf("RetryInfo = %v, want positive retry delay", retryInfo)
+	}
+}
+
+func TestRateLimiterUnary(t *testing.T) {
+	limiter := interceptor.NewRateLimiter(interceptor.RateLimit{PerSecond: 0.1, Burst: 2}, interceptor.RateLimit{})
+	i := limiter.UnaryServerInterceptor()
+	req := &pb.GetAlbumRequest{Id: "1"}
+
+	for n := range 2 {
+		if result := interceptortest.RunUnary(context.Background(), i, "/album.AlbumService/GetAlbum", req, nil); result.Err != nil {
+			t.Fatalf("call %d: error = %v", n+1, result.Err)
+		}
+	}
+
+	result := interceptortest.RunUnary(context.Background(), i, "/album.AlbumService/GetAlbum", req, nil)
+	if result.Called {
+		t.Error("handler was called over the limit")
+	}
+	assertRateLimited(t, result.Err, result.Trailer)
+
+	// 認証されていない呼び出し元は接続元のIPアドレスで区別するため、別の接続元は別のバケットを使う
+	other := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.7"), Port: 4000}})
+	result = interceptortest.RunUnary(other, i, "/album.AlbumService/GetAlbum", req, nil)
+	if result.Err != nil {
+		t.Errorf("other caller was limited: %v", result.Err)
+	}
+}
+
+func TestRateLimiterStream(t *testing.T) {
+	// ストリームの開始で1つ、メッセージで2つのトークンを使う
+	limiter := interceptor.NewRateLimiter(interceptor.RateLimit{}, interceptor.RateLimit{PerSecond: 0.1, Burst: 3})
+
+	ss := interceptortest.NewServerStream(context.Background(), "/album.AlbumService/UploadAndNotify",
+		&pb.UploadAndNotifyRequest{}, &pb.UploadAndNotifyRequest{}, &pb.UploadAndNotifyRequest{})
+	err := interceptortest.RunStream(limiter.StreamServerInterceptor(), ss, nil)
+	assertRateLimited(t, err, ss.Trailer())
+	if len(ss.Sent()) != 2 {
+		t.Errorf("handled %d messages before the limit, want 2", len(ss.Sent()))
+	}
+
+	// トークンが残っていなければ、ストリームの開始時にハンドラーを呼ばずに拒否する
+	ss = interceptortest.NewServerStream(context.Background(), "/album.AlbumService/ListAlbums", &pb.ListAlbumsRequest{})
+	ss.IsServerStream = true
+	err = interceptortest.RunStream(limiter.StreamServerInterceptor(), ss, nil)
+	if ss.Called() {
+		t.Error("handler was called over the limit")
+	}
+	assertRateLimited(t, err, ss.Trailer())
+}
@@ -0,0 +1,52 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Unary RPCの後続のインターセプターやハンドラーで発生したpanicを回復し、codes.Internalのエラーとして返すインターセプター
+// スタックトレースはリクエストIDとともにログに出力し、metricsがnilでなければpanicの数を数える
+//
+// 認証や頻度制限などのインターセプターのpanicも回復できるよう、リクエストIDとトレース、ログのインターセプターの直後に設定する
+// 後続のメトリクスのインターセプターはpanicした呼び出しを記録できないため、その数はpanicのカウンターで数える
+func RecoveryUnaryServerInterceptor(metrics *Metrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, metrics, "unary", info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// ストリーム形式のRPCの後続のインターセプターやハンドラーで発生したpanicを回復し、codes.Internalのエラーとして返すインターセプター
+func RecoveryStreamServerInterceptor(metrics *Metrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), metrics, streamKind(info), info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, metrics *Metrics, kind, fullMethod string, r interface{}) error {
+	slog.ErrorContext(ctx, "panic recovered", "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+	if metrics != nil {
+		metrics.panicRecovered(kind, fullMethod)
+	}
+
+	// panicの内容は内部の情報を含むことがあるため、クライアントには返さない
+	return status.Error(codes.Internal, "internal server error")
+}
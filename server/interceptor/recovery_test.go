@@ -0,0 +1,74 @@
+package interceptor_test
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/interceptor"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/album.AlbumService/GetAlbum"}
+	_, err := interceptor.RecoveryUnaryServerInterceptor(nil)(context.Background(), &pb.GetAlbumRequest{Id: "1"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("secret internal state")
+		})
+
+	st := status.Convert(err)
+	if st.Code() != codes.Internal {
+		t.Fatalf("code = %s, want Internal", st.Code())
+	}
+	// panicの内容はクライアントに返さない
+	if strings.Contains(st.Message(), "secret") {
+		t.Errorf("message leaks the panic value: %q", st.Message())
+	}
+}
+
+// ハンドラーだけでなく、後続のインターセプターのpanicも回復する
+func TestRecoveryUnaryServerInterceptorInterceptorPanic(t *testing.T) {
+	panicking := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		panic("interceptor bug")
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/album.AlbumService/GetAlbum"}
+	called := false
+	_, err := interceptor.RecoveryUnaryServerInterceptor(nil)(context.Background(), &pb.GetAlbumRequest{Id: "1"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return panicking(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return req, nil
+			})
+		})
+
+	if code := status.Code(err); code != codes.Internal {
+		t.Errorf("code = %s, want Internal", code)
+	}
+	if called {
+		t.Error("handler was called after the interceptor panicked")
+	}
+}
+
+func TestRecoveryStreamServerInterceptor(t *testing.T) {
+	info := &grpc.StreamServerInfo{FullMethod: "/album.AlbumService/UploadAndNotify", IsClientStream: true}
+	err := interceptor.RecoveryStreamServerInterceptor(nil)(nil, &contextStream{ctx: context.Background()}, info,
+		func(srv interface{}, stream grpc.ServerStream) error {
+			panic("boom")
+		})
+
+	if code := status.Code(err); code != codes.Internal {
+		t.Errorf("code = %s, want Internal", code)
+	}
+}
+
+// コンテキストだけを返す偽のServerStream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,83 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// リクエストIDを受け渡すメタデータのキー
+const RequestIDKey = "x-request-id"
+
+// クライアントから受け取るリクエストIDの最大長（これより長い場合は新しく採番する）
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// リクエストIDをコンテキストに設定する関数
+func NewRequestIDContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// コンテキストからリクエストIDを取り出す関数
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// Unary RPCにリクエストIDを割り当てるインターセプター
+// クライアントがメタデータでリクエストIDを送った場合はそれを使い、レスポンスのヘッダーでも返す
+// ログに付けられるよう、他のインターセプターより先に設定する
+func RequestIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := requestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, id))
+
+		return handler(NewRequestIDContext(ctx, id), req)
+	}
+}
+
+// ストリーム形式のRPCにリクエストIDを割り当てるインターセプター
+func RequestIDStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id := requestID(ss.Context())
+		ss.SetHeader(metadata.Pairs(RequestIDKey, id))
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: NewRequestIDContext(ss.Context(), id)})
+	}
+}
+
+// メタデータのリクエストIDを返す関数
+// 送られていない場合や不正な値の場合は新しく採番する
+func requestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDKey); len(values) > 0 && validRequestID(values[0]) {
+		return values[0]
+	}
+
+	return NewRequestID()
+}
+
+// 新しいリクエストIDを採番する関数
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/randのReadはエラーを返さない
+	return hex.EncodeToString(b)
+}
+
+// ログを壊さないよう、表示可能なASCII文字だけからなるIDのみ受け付ける
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
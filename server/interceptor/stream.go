@@ -1,9 +1,13 @@
 package interceptor
 
 import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // ストリーム形式のRPCに対して処理を挟むためのインターセプター
@@ -17,23 +21,93 @@ func StreamServerInterceptor() grpc.StreamServerInterceptor {
 // grpcのServerStream{}をラップし、送受信時にカスタム処理を追加する
 type wrappedServerStream struct {
 	grpc.ServerStream
+
+	// 送信と受信は別のgoroutineから呼ばれることがあるため、アトミックに数える
+	received atomic.Int64 // 受信したメッセージの数
+	sent     atomic.Int64 // 送信したメッセージの数
+
+	onRecv func(m interface{}) // メッセージを受信するたびに呼ばれる（nilの場合は何もしない）
+	onSend func(m interface{}) // メッセージを送信するたびに呼ばれる（nilの場合は何もしない）
+
+	// メッセージを受信するたびに、ハンドラーに渡す前に呼ばれる（nilの場合は何もしない）
+	// エラーを返すと、受信したメッセージを捨ててRecvMsgがそのエラーを返す
+	checkRecv func(m interface{}) error
+
+	stack *streamStack // 同じストリームをラップしたwrappedServerStreamの一覧
+}
+
+// 同じストリームを入れ子にラップしたwrappedServerStreamの一覧
+//
+// 外側のラッパーは内側のラッパーのcheckRecvより先にメッセージを受け取るため、
+// 各ラッパーが自分でメッセージを数えると、内側で拒否されたメッセージまで数えてしまう
+// そこで、受信したメッセージは最も内側のラッパーまですべての確認を通ってから、外側から順に各ラッパーで数える
+type streamStack struct {
+	layers []*wrappedServerStream // 外側から順に並べたラッパー（ハンドラーの実行前に追加し終える）
 }
 
 // メッセージの受信時に処理を挟むカスタムメソッド
 func (w *wrappedServerStream) RecvMsg(m interface{}) error {
-	Logger("Receive a message (type: %T) at %v", m, time.Now()) // メッセージの受信をログに記録
-	return w.ServerStream.RecvMsg(m)
+	err := w.ServerStream.RecvMsg(m)
+	if err != nil {
+		return err
+	}
+	if w.checkRecv != nil {
+		if err := w.checkRecv(m); err != nil {
+			return err
+		}
+	}
+
+	// 外側のラッパーでは数えず、最も内側のラッパーで確認を終えてからまとめて数える
+	if layers := w.stack.layers; layers[len(layers)-1] == w {
+		for _, layer := range layers {
+			layer.received.Add(1)
+			if layer.onRecv != nil {
+				layer.onRecv(m)
+			}
+		}
+	}
+	return nil
 }
 
 // メッセージの送信時に処理を挟むカスタムメソッド
 func (w *wrappedServerStream) SendMsg(m interface{}) error {
-	Logger("Send a message (type: %T) at %v", m, time.Now()) // メッセージの送信をログに記録
-	return w.ServerStream.SendMsg(m)
+	err := w.ServerStream.SendMsg(m)
+	if err == nil {
+		w.sent.Add(1)
+		if w.onSend != nil {
+			w.onSend(m)
+		}
+	}
+	return err
 }
 
 // ServerStreamをラップするカスタム関数
-func newWrappedServerStream(ss grpc.ServerStream) grpc.ServerStream {
-	return &wrappedServerStream{ss}
+// 受信したメッセージは最も内側のラッパーで数えるため、作成したラッパーは必ずハンドラーに渡すこと
+func newWrappedServerStream(ss grpc.ServerStream) *wrappedServerStream {
+	w := &wrappedServerStream{ServerStream: ss}
+	if outer := outerWrappedServerStream(ss); outer != nil {
+		w.stack = outer.stack
+	} else {
+		w.stack = &streamStack{}
+	}
+	w.stack.layers = append(w.stack.layers, w)
+
+	return w
+}
+
+// ssの外側（インターセプターの実行順では前）にあるwrappedServerStreamを返す関数
+// コンテキストだけを差し替えるcontextServerStreamは読み飛ばす
+func outerWrappedServerStream(ss grpc.ServerStream) *wrappedServerStream {
+	for {
+		switch s := ss.(type) {
+		case *wrappedServerStream:
+			return s
+		case *contextServerStream:
+			ss = s.ServerStream
+		default:
+			return nil
+		}
+	}
 }
 
 // リクエストとレスポンスの前後にカスタム処理を挟む関数
@@ -43,15 +117,49 @@ func StreamServerInterceptorHandler(
 	info *grpc.StreamServerInfo,
 	handler grpc.StreamHandler,
 ) error {
-	// 呼び出されたgRPCメソッドをログ出力
-	Logger("Stream Request - Method: %s", info.FullMethod)
+	start := time.Now()
 
-	err := handler(srv, newWrappedServerStream(ss))
-	// レスポンス時にエラーがあればエラーログを出力
-	if err != nil {
-		Logger("Stream Response - Method: %s, Error: %v", info.FullMethod, err)
+	ws := newWrappedServerStream(ss)
+	ws.onRecv = func(m interface{}) {
+		slog.DebugContext(ss.Context(), "message received", "type", typeName(m)) // メッセージの受信をログに記録
+	}
+	ws.onSend = func(m interface{}) {
+		slog.DebugContext(ss.Context(), "message sent", "type", typeName(m)) // メッセージの送信をログに記録
 	}
+	err := handler(srv, ws)
+
+	// メソッド、接続元、ステータスコード、処理時間に加えて、送受信したメッセージの数を記録する
+	logRPC(ss.Context(), streamKind(info), start, err,
+		slog.Int64("messages_received", ws.received.Load()),
+		slog.Int64("messages_sent", ws.sent.Load()),
+	)
 
 	// エラーを返す
 	return err
-}
\ No newline at end of file
+}
+
+func streamKind(info *grpc.StreamServerInfo) string {
+	return rpcKind(info.IsClientStream, info.IsServerStream)
+}
+
+// RPCの種類を表す文字列を返す関数
+func rpcKind(isClientStream, isServerStream bool) string {
+	switch {
+	case isClientStream && isServerStream:
+		return "bidi_stream"
+	case isClientStream:
+		return "client_stream"
+	case isServerStream:
+		return "server_stream"
+	default:
+		return "unary"
+	}
+}
+
+func typeName(m interface{}) string {
+	if msg, ok := m.(interface{ ProtoReflect() protoreflect.Message }); ok {
+		return string(msg.ProtoReflect().Descriptor().FullName())
+	}
+
+	return fmt.Sprintf("%T", m)
+}
@@ -0,0 +1,120 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 決められた数のメッセージを受信できる偽のServerStream
+type fakeServerStream struct {
+	grpc.ServerStream
+
+	remaining int
+	trailer   metadata.MD
+}
+
+func (s *fakeServerStream) Context() context.Context    { return context.Background() }
+func (s *fakeServerStream) SetTrailer(md metadata.MD)   { s.trailer = metadata.Join(s.trailer, md) }
+func (s *fakeServerStream) SendMsg(m interface{}) error { return nil }
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if s.remaining == 0 {
+		return io.EOF
+	}
+	s.remaining--
+	return nil
+}
+
+// ストリームが終わるかエラーになるまでメッセージを受信するハンドラー
+func drain(srv interface{}, stream grpc.ServerStream) error {
+	for {
+		if err := stream.RecvMsg(nil); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// 内側のラッパーで拒否されたメッセージは、外側のラッパーでも数えない
+func TestWrappedServerStreamCountsAfterChecks(t *testing.T) {
+	var outer, inner *wrappedServerStream
+	var outerSeen, innerSeen int
+	errRejected := errors.New("rejected")
+
+	outerInterceptor := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		outer = newWrappedServerStream(ss)
+		outer.onRecv = func(interface{}) { outerSeen++ }
+		return handler(srv, outer)
+	}
+	innerInterceptor := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		// コンテキストを差し替えたストリームを挟んでも、同じストリームのラッパーとして扱う
+		inner = newWrappedServerStream(&contextServerStream{ServerStream: ss, ctx: ss.Context()})
+		inner.onRecv = func(interface{}) { innerSeen++ }
+		inner.checkRecv = func(interface{}) error {
+			if inner.received.Load() == 2 {
+				return errRejected
+			}
+			return nil
+		}
+		return handler(srv, inner)
+	}
+
+	ss := &fakeServerStream{remaining: 5}
+	err := outerInterceptor(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		return innerInterceptor(srv, ss, &grpc.StreamServerInfo{}, drain)
+	})
+	if !errors.Is(err, errRejected) {
+		t.Fatalf("error = %v, want %v", err, errRejected)
+	}
+
+	for name, w := range map[string]*wrappedServerStream{"outer": outer, "inner": inner} {
+		if got := w.received.Load(); got != 2 {
+			t.Errorf("%s received = %d, want 2", name, got)
+		}
+	}
+	if outerSeen != 2 || innerSeen != 2 {
+		t.Errorf("onRecv called %d times (outer) and %d times (inner), want 2", outerSeen, innerSeen)
+	}
+}
+
+// ストリームの開始時にもトークンを1つ取り出す
+func TestRateLimiterStreamOpen(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{}, RateLimit{PerSecond: 0.1, Burst: 3})
+	i := limiter.StreamServerInterceptor()
+
+	// 開始で1つ、メッセージで2つ使うため、3つ目のメッセージで制限を超える
+	ss := &fakeServerStream{remaining: 3}
+	if err := i(nil, ss, &grpc.StreamServerInfo{}, drain); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("error = %v, want ResourceExhausted", err)
+	}
+	if ss.remaining != 0 {
+		t.Errorf("%d messages left, want 0", ss.remaining)
+	}
+
+	// トークンが残っていないため、ハンドラーを呼ばずに拒否する
+	ss = &fakeServerStream{remaining: 1}
+	called := false
+	err := i(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	if called {
+		t.Error("handler was called over the limit")
+	}
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted || len(st.Details()) != 1 {
+		t.Errorf("error = %v (details %v), want ResourceExhausted with RetryInfo", err, st.Details())
+	}
+	if values := ss.trailer.Get(RetryAfterKey); len(values) != 1 {
+		t.Errorf("retry-after trailer = %v", values)
+	}
+}
@@ -0,0 +1,59 @@
+package interceptor
+
+import (
+	"awsomeProject/tracing"
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// Unary RPCごとにスパンを作成するインターセプター
+// クライアントがメタデータで送ったトレースの情報があれば、その子スパンにする
+func TracingUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := startServerSpan(ctx, info.FullMethod)
+
+		tracing.MessageEvent(span, "RECEIVED", 1, req)
+		resp, err := handler(ctx, req)
+		if err == nil {
+			tracing.MessageEvent(span, "SENT", 1, resp)
+		}
+		tracing.EndRPC(span, err)
+
+		return resp, err
+	}
+}
+
+// ストリーム形式のRPCごとにスパンを作成するインターセプター
+// 送受信したメッセージは、wrappedServerStreamでスパンのイベントとして記録する
+func TracingStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startServerSpan(ss.Context(), info.FullMethod)
+
+		ws := newWrappedServerStream(&contextServerStream{ServerStream: ss, ctx: ctx})
+		ws.onRecv = func(m interface{}) { tracing.MessageEvent(span, "RECEIVED", ws.received.Load(), m) }
+		ws.onSend = func(m interface{}) { tracing.MessageEvent(span, "SENT", ws.sent.Load(), m) }
+
+		err := handler(srv, ws)
+		tracing.EndRPC(span, err)
+
+		return err
+	}
+}
+
+func startServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	ctx = tracing.Extract(ctx)
+
+	attrs := tracing.RPCAttributes(fullMethod)
+	if id, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, attribute.String("request_id", id))
+	}
+
+	return tracing.Tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+}
@@ -2,8 +2,13 @@ package interceptor
 
 import (
 	"context"
+	"log/slog"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 // Unary RPCに対してリクエストの前後に処理を挟むためのインターセプター
@@ -16,22 +21,57 @@ func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 
 // リクエストの前後にロギング処理を挟むための関数
 func unaryServerInterceptorHandler(
-	ctx context.Context,           // gRPCリクエストのコンテキスト（リクエストごとの設定や状態管理）
-	req interface{},               // クライアントから送信されたリクエストデータ
-	info *grpc.UnaryServerInfo,    // gRPCメソッドの情報（メソッド名など）
-	handler grpc.UnaryHandler,     // リクエストを実際に処理するハンドラ関数
+	ctx context.Context, // gRPCリクエストのコンテキスト（リクエストごとの設定や状態管理）
+	req interface{}, // クライアントから送信されたリクエストデータ
+	info *grpc.UnaryServerInfo, // gRPCメソッドの情報（メソッド名など）
+	handler grpc.UnaryHandler, // リクエストを実際に処理するハンドラ関数
 ) (interface{}, error) {
-	// 呼び出されたgRPCメソッドをログ出力
-	Logger("Unary Request - Method: %s", info.FullMethod)
+	start := time.Now()
 
 	// gRPCリクエストを処理する
 	m, err := handler(ctx, req)
 
-	// レスポンス時にエラーがあればエラーログを出力
-	if err != nil {
-		Logger("Unary Response - Method: %s, Error: %v", info.FullMethod, err)
-	}
+	// メソッド、接続元、ステータスコード、処理時間を記録する
+	logRPC(ctx, "unary", start, err)
 
 	// レスポンスを返す
 	return m, err
-}
\ No newline at end of file
+}
+
+// RPCの結果をステータスコードに応じたレベルでログに出力する関数
+func logRPC(ctx context.Context, kind string, start time.Time, err error, attrs ...slog.Attr) {
+	code := status.Code(err)
+
+	attrs = append([]slog.Attr{
+		slog.String("kind", kind),
+		slog.String("peer", peerAddr(ctx)),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}, attrs...)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+
+	slog.LogAttrs(ctx, levelForCode(code), "rpc finished", attrs...)
+}
+
+// クライアントの誤りによるエラーはInfo、サーバー側の問題の可能性があるものはWarnかErrorにする
+func levelForCode(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK, codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.Unauthenticated:
+		return slog.LevelInfo
+	case codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition, codes.Aborted, codes.OutOfRange, codes.DeadlineExceeded:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	return p.Addr.String()
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"awsomeProject/validate"
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Unary RPCのリクエストを、protoに定義した検証ルールで確認するインターセプター
+// ルールを満たさない場合はハンドラーを呼ばず、BadRequestの詳細を付けたInvalidArgumentを返す
+func ValidateUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := validateMessage(ctx, req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ストリーム形式のRPCで受信するメッセージを1つずつ検証ルールで確認するインターセプター
+// 確認はwrappedServerStreamのRecvMsgで行い、ルールを満たさない場合はRecvMsgがInvalidArgumentを返す
+func ValidateStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ws := newWrappedServerStream(ss)
+		ws.checkRecv = func(m interface{}) error {
+			return validateMessage(ss.Context(), m)
+		}
+
+		return handler(srv, ws)
+	}
+}
+
+func validateMessage(ctx context.Context, m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	err := validate.Message(msg)
+	var verr *validate.Error
+	if err != nil && !errors.As(err, &verr) {
+		// クライアントの誤りではなく、protoに書いたルール自体が不正
+		slog.ErrorContext(ctx, "invalid validation rule", "error", err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	return err
+}
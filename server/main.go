@@ -1,59 +1,101 @@
 package main
 
 import (
+	"awsomeProject/auth"
+	"awsomeProject/config"
+	"awsomeProject/money"
 	"awsomeProject/pb"
 	"awsomeProject/server/interceptor"
+	"awsomeProject/server/search"
+	"awsomeProject/server/store"
+	"awsomeProject/tlsconfig"
+	"awsomeProject/tracing"
+	"awsomeProject/validate"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"google.golang.org/grpc"
-)
-
-const (
-	filePath = "db/album.json" // JSONファイルに保存されたアルバムデータのパス
-	port     = "50051"
-
-	timeSleep = 1 * time.Second // レスポンス間のスリープ時間
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
 type AlbumServer struct {
 	pb.UnimplementedAlbumServiceServer
 
-	savedAlbums []*pb.Album // サーバーに保存されたアルバムのリスト
+	store store.AlbumStore // アルバムデータの保存先
+	index *search.Index    // 全文検索の索引（storeへの変更は自動的に反映される）
+
+	streamInterval time.Duration // ListAlbumsのレスポンス間のスリープ時間（動作確認用、デフォルトは0）
 }
 
 // Unary RPC
-// クライアントから送信されたアルバムのタイトルに基づいて、アルバム情報を返すメソッド
+// クライアントから送信されたアルバムのIDまたはタイトルに基づいて、アルバム情報を返すメソッド
 func (s *AlbumServer) GetAlbum(ctx context.Context, req *pb.GetAlbumRequest) (*pb.GetAlbumResponse, error) {
-	for _, album := range s.savedAlbums {
-		if album.Title == req.Title {
-			log.Printf("album found: %s", req.Title)
-			return &pb.GetAlbumResponse{Album: album}, nil
-		}
+	if req.Id == "" && req.Title == "" {
+		return nil, invalidArgumentError("title", "either id or title must be set")
+	}
+
+	album, err := s.findAlbum(ctx, req.Id, req.Title)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("album not found: %s", req.Title)
-	return &pb.GetAlbumResponse{Album: &pb.Album{}}, nil
+	slog.DebugContext(ctx, "album found", "id", album.Id, "title", album.Title)
+	return &pb.GetAlbumResponse{Album: album}, nil
 }
 
 // Server Streaming RPC
-// クライアントからartistを受け取り、artistが一致するAlbumをすべてAlbum型で返すメソッド
+// クライアントから絞り込み条件を受け取り、条件に一致するAlbumを1ページ分Album型で返すメソッド
+// 次のページがある場合は、ページの最後のレスポンスにnext_page_tokenを設定する
 func (s *AlbumServer) ListAlbums(req *pb.ListAlbumsRequest, stream pb.AlbumService_ListAlbumsServer) error {
-	log.Printf("request: %s", req.Artist)
+	slog.DebugContext(stream.Context(), "list albums", "artist", req.Artist, "filter", req.Filter.String())
 
-	for _, album := range s.savedAlbums {
-		if album.Artist == req.Artist {
-			// ストリーム形式のレスポンス
-			if err := stream.Send(&pb.ListAlbumsResponse{Album: album}); err != nil {
-				return err
-			}
-			time.Sleep(timeSleep)
+	filter := req.Filter
+	if req.Artist != "" {
+		if filter != nil {
+			return invalidArgumentError("artist", "must not be set together with filter")
+		}
+		filter = &pb.AlbumFilter{Artist: req.Artist}
+	}
+
+	page, err := s.listPage(stream.Context(), listQuery{
+		filter:    filter,
+		pageSize:  req.PageSize,
+		pageToken: req.PageToken,
+		orderBy:   req.OrderBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, album := range page.albums {
+		res := &pb.ListAlbumsResponse{Album: album}
+		if i == len(page.albums)-1 {
+			res.NextPageToken = page.nextPageToken
+		}
+
+		// ストリーム形式のレスポンス
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+		if err := sleep(stream.Context(), s.streamInterval); err != nil {
+			return status.FromContextError(err).Err()
 		}
 	}
 
@@ -61,11 +103,11 @@ func (s *AlbumServer) ListAlbums(req *pb.ListAlbumsRequest, stream pb.AlbumServi
 }
 
 // Client Streaming RPC
-// クライアントから複数のtitleを受け取り、ファイルに存在するAlbumの総数・合計金額・メッセージを返すメソッド
+// クライアントから複数のtitleを受け取り、ファイルに存在するAlbumの総数・通貨ごとの合計金額・メッセージを返すメソッド
 func (s *AlbumServer) GetTotalAmount(stream pb.AlbumService_GetTotalAmountServer) error {
 	var (
-		albumCount int32
-		totalAmount float32
+		albumCount  int32
+		totalAmount money.Sum // floatの誤差が出ないよう、通貨ごとに整数で合計する
 	)
 
 	for {
@@ -74,8 +116,8 @@ func (s *AlbumServer) GetTotalAmount(stream pb.AlbumService_GetTotalAmountServer
 			return stream.SendAndClose(
 				&pb.GetTotalAmountResponse{
 					AlbumCount: albumCount,
-					TotalAmount: totalAmount,
-					Message: "success to get total amount",
+					Totals:     totalAmount.Totals(),
+					Message:    "success to get total amount",
 				},
 			)
 		}
@@ -83,15 +125,27 @@ func (s *AlbumServer) GetTotalAmount(stream pb.AlbumService_GetTotalAmountServer
 			return err
 		}
 
+		slog.DebugContext(stream.Context(), "add album to total", "id", req.Id, "title", req.Title)
+		if req.Id == "" && req.Title == "" {
+			return invalidArgumentError("title", "either id or title must be set")
+		}
+
 		albumCount++
 
-		log.Printf("request: %s", req.Title)
-		// クライアントから受け取ったタイトルに基づいてアルバムを検索
-		for _, album := range s.savedAlbums {
-			if album.Title == req.Title {
-				totalAmount += album.Price
-				break
-			}
+		// クライアントから受け取ったIDまたはタイトルに基づいてアルバムを検索
+		// 存在しないアルバムは合計金額に含めない
+		album, err := s.findAlbum(stream.Context(), req.Id, req.Title)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if album.Price == nil {
+			continue // 価格が未設定のアルバムは合計に含めない
+		}
+		if err := totalAmount.Add(album.Price); err != nil {
+			return status.Errorf(codes.OutOfRange, "failed to sum prices: %v", err)
 		}
 	}
 }
@@ -109,29 +163,19 @@ func (s *AlbumServer) UploadAndNotify(stream pb.AlbumService_UploadAndNotifyServ
 			return err
 		}
 
-		log.Printf("request: %s", req.Album.Title)
-		res := &pb.UploadAndNotifyResponse{}
-
-		// 既存のアルバムか確認
-		for _, album := range s.savedAlbums {
-			if album.Title == req.Album.Title {
-				res.Message = fmt.Sprintf("%s is already exists", req.Album.Title)
-				break
-			}
+		// 内容はバリデーションのインターセプターで受信時に確認済み
+		if req.Album == nil {
+			return invalidArgumentError("album", "must be set")
 		}
 
-		// 新規アルバムであれば保存
-		if res.Message == "" {
-			s.savedAlbums = append(s.savedAlbums, req.Album)
-			s.UpdateAlbums(s.savedAlbums, filePath) // アルバムをファイルに保存
-			res.Message = fmt.Sprintf("%s is uploaded", req.Album.Title)
+		slog.DebugContext(stream.Context(), "upload album", "title", req.Album.Title)
 
-			// アルバムデータをファイルに保存
-			if err := s.UpdateAlbums(s.savedAlbums, filePath); err != nil {
-				log.Printf("failed to update albums: %v", err)
-				return err
-			}
+		// 新規アルバムであれば保存（既存のアルバムであればAlreadyExistsを返してストリームを終了する）
+		album, err := s.store.Insert(stream.Context(), newAlbum(req.Album))
+		if err != nil {
+			return storeError(stream.Context(), err, req.Album.Title)
 		}
+		res := &pb.UploadAndNotifyResponse{Message: fmt.Sprintf("%s is uploaded (id: %s)", album.Title, album.Id)}
 
 		// レスポンスをストリームに送信
 		if err := stream.Send(res); err != nil {
@@ -141,70 +185,454 @@ func (s *AlbumServer) UploadAndNotify(stream pb.AlbumService_UploadAndNotifyServ
 
 }
 
-// サーバーの初期化時にアルバムデータをロードするメソッド
-func (s *AlbumServer) loadAlbums(filePath string) error {
-	var (
-		data []byte
-		err  error
-	)
-	data, err = os.ReadFile(filePath) // ファイルからアルバム情報を読み取る
-	if err != nil {
-		return err
+// Unary RPC
+// クライアントから受け取ったアルバムを新規作成するメソッド
+func (s *AlbumServer) CreateAlbum(ctx context.Context, req *pb.CreateAlbumRequest) (*pb.CreateAlbumResponse, error) {
+	// 内容はバリデーションのインターセプターで確認済み
+	if req.Album == nil {
+		return nil, invalidArgumentError("album", "must be set")
 	}
 
-    // JSONデータをGoの構造体に変換しsavedAlbumsに保存する
-	if err := json.Unmarshal(data, &s.savedAlbums); err != nil {
-		return err
+	album, err := s.store.Insert(ctx, newAlbum(req.Album))
+	if err != nil {
+		return nil, storeError(ctx, err, req.Album.Title)
 	}
 
-	return nil
+	slog.InfoContext(ctx, "album created", "id", album.Id, "title", album.Title)
+	return &pb.CreateAlbumResponse{Album: album}, nil
 }
 
-func (s *AlbumServer) UpdateAlbums(albums []*pb.Album, filePath string) error {
-	newFile, err := os.Create(filePath)
+// Unary RPC
+// update_maskで指定されたフィールドだけを書き換えてアルバムを更新するメソッド
+func (s *AlbumServer) UpdateAlbum(ctx context.Context, req *pb.UpdateAlbumRequest) (*pb.UpdateAlbumResponse, error) {
+	if req.Album == nil {
+		return nil, invalidArgumentError("album", "must be set")
+	}
+	if req.Album.Id == "" {
+		return nil, invalidArgumentError("album.id", "must not be empty")
+	}
+
+	album, err := s.store.Get(ctx, req.Album.Id)
 	if err != nil {
-		return err
+		return nil, storeError(ctx, err, req.Album.Id)
 	}
-	defer newFile.Close()
 
-	// albumリストをjson形式に変換
-	newData, err := json.MarshalIndent(albums, "", "  ")
+	if err := applyUpdateMask(album, req.Album, req.UpdateMask); err != nil {
+		return nil, err
+	}
+	if err := validateAlbum(album); err != nil {
+		return nil, err
+	}
+
+	album, err = s.store.Update(ctx, album)
 	if err != nil {
-		return err
+		return nil, storeError(ctx, err, req.Album.Id)
+	}
+
+	slog.InfoContext(ctx, "album updated", "id", album.Id, "title", album.Title)
+	return &pb.UpdateAlbumResponse{Album: album}, nil
+}
+
+// Unary RPC
+// IDで指定されたアルバムを削除するメソッド
+func (s *AlbumServer) DeleteAlbum(ctx context.Context, req *pb.DeleteAlbumRequest) (*pb.DeleteAlbumResponse, error) {
+	if req.Id == "" {
+		return nil, invalidArgumentError("id", "must not be empty")
 	}
 
-	// 新しいファイルに書き込む
-	_, err = newFile.Write(newData)
+	if err := s.store.Delete(ctx, req.Id); err != nil {
+		return nil, storeError(ctx, err, req.Id)
+	}
+
+	slog.InfoContext(ctx, "album deleted", "id", req.Id)
+	return &pb.DeleteAlbumResponse{}, nil
+}
+
+// Unary RPC
+// ListAlbumsと同じ条件で、1ページ分のAlbumと次のページのトークンをまとめて返すメソッド
+// queryを指定した場合は全文検索を行い、関連度の高い順に結果を返す
+func (s *AlbumServer) SearchAlbums(ctx context.Context, req *pb.SearchAlbumsRequest) (*pb.SearchAlbumsResponse, error) {
+	page, err := s.listPage(ctx, listQuery{
+		filter:    req.Filter,
+		pageSize:  req.PageSize,
+		pageToken: req.PageToken,
+		orderBy:   req.OrderBy,
+		query:     req.Query,
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	res := &pb.SearchAlbumsResponse{
+		Albums:        page.albums,
+		NextPageToken: page.nextPageToken,
+		TotalSize:     int32(page.totalSize),
+	}
+	for _, result := range page.results {
+		res.Results = append(res.Results, &pb.SearchResult{
+			Album:        result.Album,
+			Score:        result.Score,
+			MatchedTerms: result.MatchedTerms,
+		})
+	}
+
+	return res, nil
+}
+
+// IDが指定されていればIDで、そうでなければタイトルでアルバムを検索するメソッド
+// 同じタイトルのアルバムが複数ある場合は最初に登録されたものを返す
+func (s *AlbumServer) findAlbum(ctx context.Context, id, title string) (*pb.Album, error) {
+	if id != "" {
+		album, err := s.store.Get(ctx, id)
+		if err != nil {
+			return nil, storeError(ctx, err, id)
+		}
+		return album, nil
+	}
+
+	albums, _, err := s.store.List(ctx, store.Filter{Title: title}, store.ListOptions{Limit: 1})
+	if err != nil {
+		return nil, storeError(ctx, err, title)
+	}
+	if len(albums) == 0 {
+		return nil, storeError(ctx, store.ErrNotFound, title)
+	}
+
+	return albums[0], nil
+}
+
+// dだけ待つ関数
+// 待っている間にRPCがキャンセルされたり、サーバーが停止したりした場合はすぐに戻る
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// 保存するアルバムの内容をprotoに定義した検証ルールで確認する関数
+// UpdateAlbumのリクエストのアルバムは一部のフィールドだけを持つため、update_maskを反映した後のアルバムをこの関数で確認する
+func validateAlbum(album *pb.Album) error {
+	return validate.MessageAt("album", album)
+}
+
+// クライアントから受け取ったアルバムから、サーバーが設定するフィールドを取り除く関数
+// IDと作成・更新日時はストアが設定する
+func newAlbum(src *pb.Album) *pb.Album {
+	album := proto.Clone(src).(*pb.Album)
+	album.Id = ""
+	album.CreatedAt = nil
+	album.UpdatedAt = nil
+
+	return album
+}
+
+// update_maskに従ってsrcのフィールドをdstにコピーする関数
+// マスクが空の場合は、サーバーが設定するフィールド以外のすべてを更新する
+func applyUpdateMask(dst, src *pb.Album, mask *fieldmaskpb.FieldMask) error {
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		paths = []string{"title", "artist", "price", "release_year", "genre", "tracks"}
+	}
+	if !mask.IsValid(dst) {
+		return invalidArgumentError("update_mask", "contains unknown field")
+	}
+
+	for _, path := range paths {
+		switch path {
+		case "title":
+			dst.Title = src.Title
+		case "artist":
+			dst.Artist = src.Artist
+		case "price":
+			dst.Price = src.Price
+		case "release_year":
+			dst.ReleaseYear = src.ReleaseYear
+		case "genre":
+			dst.Genre = src.Genre
+		case "tracks":
+			dst.Tracks = src.Tracks
+		default:
+			// id, created_at, updated_atはサーバーが設定するため変更できない
+			return invalidArgumentError("update_mask", fmt.Sprintf("field %q cannot be updated", path))
+		}
 	}
 
 	return nil
 }
 
-func newServer() *AlbumServer {
-	s := &AlbumServer{}
-	if err := s.loadAlbums(filePath); err != nil { // サーバー起動時にアルバムデータをロード
-		log.Fatalf("failed to load albums: %v", err)
+// 設定で指定されたバックエンドのストアを開く関数
+func openStore(cfg config.Storage) (store.AlbumStore, error) {
+	switch cfg.Backend {
+	case "json":
+		// JSONファイルからアルバムデータをロード
+		return store.NewJSONStore(cfg.JSONPath)
+	case "sqlite":
+		albumStore, err := store.NewSQLiteStore(cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+
+		// 既存のJSONファイルのデータを取り込む
+		if cfg.ImportPath != "" {
+			n, err := store.ImportJSON(context.Background(), albumStore, cfg.ImportPath)
+			if err != nil {
+				albumStore.Close()
+				return nil, err
+			}
+			slog.Info("imported albums", "count", n, "path", cfg.ImportPath)
+		}
+
+		return albumStore, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %q", cfg.Backend)
+	}
+}
+
+// 設定に従ってgRPCサーバーのオプションを組み立てる関数
+// metricsがnilの場合はメトリクスを記録しない
+func serverOptions(cfg *config.Server, metrics *interceptor.Metrics) ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		// 強制停止したときも、実行中のハンドラーが終わってからストアを閉じられるようにする
+		grpc.WaitForHandlers(true),
+	}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, err := tlsconfig.Server(cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	// インターセプターは追加した順ではなく、interceptor.Chainで決められた順に実行される
+	chain := interceptor.NewChain()
+	chain.Add(interceptor.NameRequestID, interceptor.RequestIDUnaryServerInterceptor(), interceptor.RequestIDStreamServerInterceptor())
+	if cfg.Tracing.Exporter != tracing.ExporterNone {
+		chain.Add(interceptor.NameTracing, interceptor.TracingUnaryServerInterceptor(), interceptor.TracingStreamServerInterceptor())
+	}
+	if cfg.Interceptors.Logging {
+		chain.Add(interceptor.NameLogging, interceptor.UnaryServerInterceptor(), interceptor.StreamServerInterceptor())
+	}
+	chain.Add(interceptor.NameRecovery, interceptor.RecoveryUnaryServerInterceptor(metrics), interceptor.RecoveryStreamServerInterceptor(metrics))
+	if metrics != nil {
+		chain.Add(interceptor.NameMetrics, metrics.UnaryServerInterceptor(), metrics.StreamServerInterceptor())
+	}
+	if cfg.Auth.Enabled {
+		authenticator, err := newAuthenticator(cfg.Auth)
+		if err != nil {
+			return nil, err
+		}
+		chain.Add(interceptor.NameAuth,
+			interceptor.AuthUnaryServerInterceptor(authenticator, cfg.Auth.PublicMethods),
+			interceptor.AuthStreamServerInterceptor(authenticator, cfg.Auth.PublicMethods))
+	}
+	if cfg.RateLimit.Enabled {
+		limiter := interceptor.NewRateLimiter(
+			interceptor.RateLimit{PerSecond: cfg.RateLimit.UnaryPerSecond, Burst: cfg.RateLimit.UnaryBurst},
+			interceptor.RateLimit{PerSecond: cfg.RateLimit.StreamMessagesPerSecond, Burst: cfg.RateLimit.StreamBurst},
+		)
+		chain.Add(interceptor.NameRateLimit, limiter.UnaryServerInterceptor(), limiter.StreamServerInterceptor())
+	}
+	if cfg.Auth.Enabled && cfg.Auth.PolicyFile != "" {
+		policy, err := auth.LoadPolicy(cfg.Auth.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		chain.Add(interceptor.NameAuthz,
+			interceptor.AuthzUnaryServerInterceptor(policy, cfg.Auth.PublicMethods),
+			interceptor.AuthzStreamServerInterceptor(policy, cfg.Auth.PublicMethods))
+	}
+	chain.Add(interceptor.NameValidate, interceptor.ValidateUnaryServerInterceptor(), interceptor.ValidateStreamServerInterceptor())
+
+	for name, filter := range cfg.Interceptors.Methods {
+		if err := chain.SetFilter(name, interceptor.MethodFilter{Include: filter.Include, Exclude: filter.Exclude}); err != nil {
+			return nil, fmt.Errorf("interceptors.methods: %w", err)
+		}
+	}
+	opts = append(opts, chain.ServerOptions()...)
+
+	if cfg.Limits.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(cfg.Limits.MaxRecvMsgSize))
+	}
+	if cfg.Limits.MaxSendMsgSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(cfg.Limits.MaxSendMsgSize))
+	}
+	if cfg.Limits.MaxConcurrentStreams > 0 {
+		opts = append(opts, grpc.MaxConcurrentStreams(cfg.Limits.MaxConcurrentStreams))
 	}
 
-	return s
+	return opts, nil
+}
+
+// 設定されたAPIキーとJWTの鍵を読み込んでAuthenticatorを作成する関数
+func newAuthenticator(cfg config.Auth) (*auth.Authenticator, error) {
+	opts := auth.Options{
+		JWTIssuer:   cfg.JWTIssuer,
+		JWTAudience: cfg.JWTAudience,
+	}
+
+	if cfg.APIKeysFile != "" {
+		keys, err := auth.LoadAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load api keys: %w", err)
+		}
+		opts.APIKeys = keys
+	}
+	if cfg.JWTSecretFile != "" {
+		secret, err := auth.LoadSecret(cfg.JWTSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jwt secret: %w", err)
+		}
+		opts.JWTSecret = secret
+	}
+
+	return auth.NewAuthenticator(opts)
+}
+
+func tracingOptions(cfg config.Tracing) tracing.Options {
+	return tracing.Options{
+		Exporter:     cfg.Exporter,
+		OTLPEndpoint: cfg.OTLPEndpoint,
+		OTLPInsecure: cfg.OTLPInsecure,
+		SampleRatio:  cfg.SampleRatio,
+	}
+}
+
+func newServer(cfg *config.Server, albumStore *search.IndexedStore) *AlbumServer {
+	return &AlbumServer{
+		store:          albumStore,
+		index:          albumStore.Index(),
+		streamInterval: cfg.StreamInterval,
+	}
 }
 
 func main() {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	// 設定の誤りは起動時にまとめて報告する
+	cfg, err := config.LoadServer(os.Args[0], os.Args[1:])
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		log.Fatalf("failed to load config: %v", err)
 	}
 
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptor.UnaryServerInterceptor()), // Unary RPCのインターセプターを設定
-		grpc.StreamInterceptor(interceptor.StreamServerInterceptor()), // Stream RPCのインターセプターを設定
+	// 以降のログはslogで構造化して出力する（logパッケージの出力もslogを経由する）
+	slog.SetDefault(interceptor.NewLogger(os.Stderr, cfg.Log.SlogLevel(), cfg.Log.Format))
+
+	// トレースの出力先を設定する（noneの場合はスパンを記録しない）
+	exporter, err := tracing.NewExporter(context.Background(), tracingOptions(cfg.Tracing))
+	if err != nil {
+		log.Fatalf("failed to create trace exporter: %v", err)
+	}
+	tracerProvider := tracing.NewTracerProvider(exporter, "album-server", cfg.Tracing.SampleRatio)
+
+	// サーバー起動時にアルバムデータをロード
+	albumStore, err := openStore(cfg.Storage)
+	if err != nil {
+		log.Fatalf("failed to load albums: %v", err)
+	}
+	if exporter != nil {
+		// ストアの操作をRPCのスパンの子スパンとして記録する
+		albumStore = store.NewTracedStore(albumStore, tracing.Tracer())
+	}
+
+	// 全文検索の索引を作成（以降のストアへの変更は索引にも反映される）
+	indexedStore, err := search.NewIndexedStore(context.Background(), albumStore)
+	if err != nil {
+		log.Fatalf("failed to build search index: %v", err)
+	}
+
+	// メトリクスは専用のレジストリに登録し、/metricsで公開する
+	var (
+		registry *prometheus.Registry
+		metrics  *interceptor.Metrics
 	)
-	pb.RegisterAlbumServiceServer(grpcServer, newServer()) // 作成したサーバーをgrpcServerに登録
+	if cfg.Metrics.Enabled {
+		registry = prometheus.NewRegistry()
+		registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+			newCatalogueCollector(indexedStore, cfg.Storage),
+		)
+		metrics = interceptor.NewMetrics(registry)
+	}
+
+	opts, err := serverOptions(cfg, metrics)
+	if err != nil {
+		log.Fatalf("failed to configure server: %v", err)
+	}
 
-	log.Println("server started")
-	if err := grpcServer.Serve(lis); err != nil { // grpcServerを起動
+	lis, err := net.Listen("tcp", cfg.ListenAddr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+
+	// 停止のシグナルはサーバーの起動前から受け取れるようにしておく
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterAlbumServiceServer(grpcServer, newServer(cfg, indexedStore)) // 作成したサーバーをgrpcServerに登録
+
+	// ストアの状態をgrpc.health.v1.Healthで公開する
+	health := newHealthChecker(indexedStore, cfg.Health.CheckInterval, cfg.Health.CheckTimeout)
+	healthpb.RegisterHealthServer(grpcServer, health.server)
+
+	// 開発用にサーバーリフレクション（v1とv1alpha）を登録する
+	if cfg.Reflection {
+		reflection.Register(grpcServer)
+		slog.Info("server reflection enabled")
+	}
+
+	var metricsServer *http.Server
+	if metrics != nil {
+		metrics.InitializeMetrics(grpcServer)
+		metricsServer, err = serveMetrics(cfg.Metrics.ListenAddr, cfg.Metrics.Path, registry)
+		if err != nil {
+			log.Fatalf("failed to start metrics server: %v", err)
+		}
+	}
+
+	healthCtx, stopHealth := context.WithCancel(context.Background())
+	healthDone := make(chan struct{})
+	go func() {
+		health.run(healthCtx)
+		close(healthDone)
+	}()
+
+	slog.Info("server started", "addr", lis.Addr().String())
+	if err := serve(grpcServer, lis, health, signals, cfg.ShutdownTimeout); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
-}
\ No newline at end of file
+
+	// ストアを閉じる前にヘルスチェックの確認とメトリクスの公開を止める
+	stopHealth()
+	<-healthDone
+	if metricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			slog.Warn("failed to stop metrics server", "error", err)
+		}
+		cancel()
+	}
+
+	// すべてのRPCが終わってから、ストアに未反映の変更を書き出して閉じる
+	if err := albumStore.Close(); err != nil {
+		log.Fatalf("failed to close album store: %v", err)
+	}
+
+	// 残りのスパンを書き出す
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		slog.Warn("failed to flush trace spans", "error", err)
+	}
+	slog.Info("server stopped")
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/search"
+	"awsomeProject/server/store"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// テストで最初から登録しておくアルバム
+func seedAlbums() []*pb.Album {
+	return []*pb.Album{
+		{Title: "Blue Train", Artist: "John Coltrane", Price: &moneypb.Money{CurrencyCode: "USD", Units: 56, Nanos: 990000000}},
+		{Title: "Jeru", Artist: "Gerry Mulligan", Price: &moneypb.Money{CurrencyCode: "USD", Units: 17, Nanos: 990000000}},
+		{Title: "Kind of Blue", Artist: "Miles Davis", Price: &moneypb.Money{CurrencyCode: "USD", Units: 29, Nanos: 990000000}},
+	}
+}
+
+// albumStoreを使うAlbumServerを作成する関数
+func newTestAlbumServer(t *testing.T, albumStore store.AlbumStore) *AlbumServer {
+	t.Helper()
+
+	indexed, err := search.NewIndexedStore(context.Background(), albumStore)
+	if err != nil {
+		t.Fatalf("failed to build search index: %v", err)
+	}
+
+	return &AlbumServer{store: indexed, index: indexed.Index()}
+}
+
+// grpcServerをメモリ上の接続で起動し、接続済みのクライアントの接続を返す関数
+// サーバーと接続はテストの終了時に閉じる
+func serveBufconn(t *testing.T, grpcServer *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// albumStoreを使うAlbumServiceをメモリ上の接続で起動し、クライアントを返す関数
+func startAlbumService(t *testing.T, albumStore store.AlbumStore, opts ...grpc.ServerOption) pb.AlbumServiceClient {
+	t.Helper()
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterAlbumServiceServer(grpcServer, newTestAlbumServer(t, albumStore))
+
+	return pb.NewAlbumServiceClient(serveBufconn(t, grpcServer))
+}
+
+func newTestJSONStore(t *testing.T) (*store.JSONStore, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "album.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	albumStore, err := store.NewJSONStore(path)
+	if err != nil {
+		t.Fatalf("failed to open json store: %v", err)
+	}
+	ctx := context.Background()
+	for _, album := range seedAlbums() {
+		if _, err := albumStore.Insert(ctx, album); err != nil {
+			t.Fatalf("failed to insert seed album: %v", err)
+		}
+	}
+
+	return albumStore, path
+}
+
+// 4つのRPCを同時に呼び出し続け、ストアの読み書きが競合しないことを確認する
+// go test -raceで実行すると、ロックの漏れをデータ競合として検出できる
+func TestConcurrentRPCs(t *testing.T) {
+	t.Run("MemoryStore", func(t *testing.T) {
+		albumStore := store.NewMemoryStore(seedAlbums()...)
+		hammer(t, albumStore)
+	})
+
+	t.Run("JSONStore", func(t *testing.T) {
+		albumStore, path := newTestJSONStore(t)
+		uploaded := hammer(t, albumStore)
+		if err := albumStore.Close(); err != nil {
+			t.Fatalf("failed to close json store: %v", err)
+		}
+
+		// アップロードしたアルバムがすべてファイルに保存されている
+		reopened, err := store.NewJSONStore(path)
+		if err != nil {
+			t.Fatalf("failed to reopen json store: %v", err)
+		}
+		defer reopened.Close()
+		_, total, err := reopened.List(context.Background(), store.Filter{}, store.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := len(seedAlbums()) + uploaded; total != want {
+			t.Errorf("albums in file = %d, want %d", total, want)
+		}
+	})
+}
+
+// GetAlbum・ListAlbums・GetTotalAmount・UploadAndNotifyを並行に呼び出し、アップロードしたアルバムの数を返す関数
+func hammer(t *testing.T, albumStore store.AlbumStore) int {
+	t.Helper()
+
+	const (
+		workers    = 8
+		iterations = 10
+		perUpload  = 3
+	)
+
+	client := startAlbumService(t, albumStore)
+	ctx := context.Background()
+	seeds := seedAlbums()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*4)
+	run := func(name string, fn func(worker, i int) error) {
+		for w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range iterations {
+					if err := fn(w, i); err != nil {
+						errs <- fmt.Errorf("%s (worker %d, iteration %d): %w", name, w, i, err)
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	run("GetAlbum", func(w, i int) error {
+		title := seeds[(w+i)%len(seeds)].Title
+		resp, err := client.GetAlbum(ctx, &pb.GetAlbumRequest{Title: title})
+		if err != nil {
+			return err
+		}
+		if resp.Album.Title != title {
+			return fmt.Errorf("got album %q, want %q", resp.Album.Title, title)
+		}
+		return nil
+	})
+
+	run("ListAlbums", func(w, i int) error {
+		stream, err := client.ListAlbums(ctx, &pb.ListAlbumsRequest{PageSize: 1000})
+		if err != nil {
+			return err
+		}
+		ids := map[string]bool{}
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			// 一貫したスナップショットを返していれば、同じアルバムが2回返ることはない
+			if ids[resp.Album.Id] {
+				return fmt.Errorf("album %s streamed twice", resp.Album.Id)
+			}
+			ids[resp.Album.Id] = true
+		}
+		if len(ids) < len(seeds) {
+			return fmt.Errorf("streamed %d albums, want at least %d", len(ids), len(seeds))
+		}
+		return nil
+	})
+
+	run("GetTotalAmount", func(w, i int) error {
+		stream, err := client.GetTotalAmount(ctx)
+		if err != nil {
+			return err
+		}
+		for _, album := range seeds {
+			if err := stream.Send(&pb.GetTotalAmountRequest{Title: album.Title}); err != nil {
+				return err
+			}
+		}
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		if resp.AlbumCount != int32(len(seeds)) {
+			return fmt.Errorf("album count = %d, want %d", resp.AlbumCount, len(seeds))
+		}
+		// シードのアルバムは書き換えないため、合計金額は常に同じ
+		if len(resp.Totals) != 1 || resp.Totals[0].Units != 104 || resp.Totals[0].Nanos != 970000000 {
+			return fmt.Errorf("totals = %v, want USD 104.97", resp.Totals)
+		}
+		return nil
+	})
+
+	run("UploadAndNotify", func(w, i int) error {
+		stream, err := client.UploadAndNotify(ctx)
+		if err != nil {
+			return err
+		}
+		for n := range perUpload {
+			album := &pb.Album{
+				Title:  fmt.Sprintf("Album %d-%d-%d", w, i, n),
+				Artist: "Concurrent Artist",
+				Price:  &moneypb.Money{CurrencyCode: "USD", Units: 10},
+			}
+			if err := stream.Send(&pb.UploadAndNotifyRequest{Album: album}); err != nil {
+				return err
+			}
+			if _, err := stream.Recv(); err != nil {
+				return err
+			}
+		}
+		if err := stream.CloseSend(); err != nil {
+			return err
+		}
+		if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("stream did not end cleanly: %v", err)
+		}
+		return nil
+	})
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	uploaded := workers * iterations * perUpload
+	_, total, err := albumStore.List(ctx, store.Filter{}, store.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(seeds) + uploaded; total != want {
+		t.Errorf("albums in store = %d, want %d", total, want)
+	}
+
+	return uploaded
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"awsomeProject/config"
+	"awsomeProject/server/store"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// カタログ全体の状態をメトリクスとして出力するprometheus.Collector
+// 値は/metricsが読まれるたびにストアから取得する
+type catalogueCollector struct {
+	store   store.AlbumStore
+	backend string        // ストアの種類（jsonまたはsqlite）
+	files   []string      // ストアのデータを保存しているファイル（合計をストアのサイズとする）
+	timeout time.Duration // ストアから件数を取得するときのタイムアウト
+
+	albums    *prometheus.Desc
+	storeSize *prometheus.Desc
+}
+
+func newCatalogueCollector(albumStore store.AlbumStore, cfg config.Storage) *catalogueCollector {
+	return &catalogueCollector{
+		store:   albumStore,
+		backend: cfg.Backend,
+		files:   storeFiles(cfg),
+		timeout: 5 * time.Second,
+		albums: prometheus.NewDesc(
+			"album_catalogue_albums",
+			"Number of albums in the catalogue.",
+			nil, nil,
+		),
+		storeSize: prometheus.NewDesc(
+			"album_catalogue_store_size_bytes",
+			"Size on disk of the files backing the album store.",
+			[]string{"backend"}, nil,
+		),
+	}
+}
+
+func (c *catalogueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.albums
+	ch <- c.storeSize
+}
+
+func (c *catalogueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	// 総数だけが必要なため、取得するアルバムは1件に絞る
+	_, total, err := c.store.List(ctx, store.Filter{}, store.ListOptions{Limit: 1})
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.albums, err)
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.albums, prometheus.GaugeValue, float64(total))
+	}
+
+	var size int64
+	for _, file := range c.files {
+		info, err := os.Stat(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // ジャーナルやWALは存在しないことがある
+		}
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(c.storeSize, err)
+			return
+		}
+		size += info.Size()
+	}
+	ch <- prometheus.MustNewConstMetric(c.storeSize, prometheus.GaugeValue, float64(size), c.backend)
+}
+
+// ストアがデータを保存しているファイルの一覧を返す関数
+func storeFiles(cfg config.Storage) []string {
+	switch cfg.Backend {
+	case "json":
+		return []string{cfg.JSONPath, cfg.JSONPath + ".journal"}
+	case "sqlite":
+		return []string{cfg.SQLitePath, cfg.SQLitePath + "-wal", cfg.SQLitePath + "-shm"}
+	default:
+		return nil
+	}
+}
+
+// /metricsでメトリクスを公開するHTTPサーバーを起動する関数
+// 起動に失敗した場合はエラーを返し、以降のエラーはログに出力する
+func serveMetrics(addr, path string, gatherer prometheus.Gatherer) (*http.Server, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(path, promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server stopped", "error", err)
+		}
+	}()
+	slog.Info("metrics server started", "addr", lis.Addr().String(), "path", path)
+
+	return srv, nil
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"awsomeProject/money"
+	"awsomeProject/pb"
+	"awsomeProject/server/search"
+	"awsomeProject/server/store"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultPageSize = 50   // page_sizeが省略された場合の1ページの件数
+	maxPageSize     = 1000 // page_sizeの上限（超えた場合は上限に切り詰める）
+)
+
+// ListAlbumsとSearchAlbumsで共通の一覧取得のリクエスト
+type listQuery struct {
+	filter    *pb.AlbumFilter
+	pageSize  int32
+	pageToken string
+	orderBy   string
+	query     string // 全文検索のキーワード（SearchAlbumsのみ）
+}
+
+// 一覧取得の1ページ分の結果
+type listPage struct {
+	albums        []*pb.Album
+	results       []search.Result // 全文検索の場合はalbumsの代わりに設定する
+	nextPageToken string          // 次のページがない場合は空
+	totalSize     int
+}
+
+// page_tokenの中身
+// クライアントには中身を意識させないよう、JSONをbase64にエンコードして渡す
+type pageToken struct {
+	Offset int    `json:"o"` // 次のページの先頭の位置
+	Query  uint64 `json:"q"` // トークンを発行したときの条件のハッシュ（別の条件で使われていないか確認する）
+}
+
+// 条件に一致するアルバムを1ページ分取得するメソッド
+func (s *AlbumServer) listPage(ctx context.Context, q listQuery) (*listPage, error) {
+	filter, err := toStoreFilter(q.filter)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseOrderBy(q.orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.pageSize < 0 {
+		return nil, invalidArgumentError("page_size", "must not be negative")
+	}
+	opts.Limit = int(q.pageSize)
+	if opts.Limit == 0 {
+		opts.Limit = defaultPageSize
+	}
+	opts.Limit = min(opts.Limit, maxPageSize)
+
+	fingerprint := queryFingerprint(q)
+	if q.pageToken != "" {
+		token, err := decodePageToken(q.pageToken)
+		if err != nil || token.Query != fingerprint {
+			return nil, invalidArgumentError("page_token", "invalid or does not match the request")
+		}
+		opts.Offset = token.Offset
+	}
+
+	var (
+		page  *listPage
+		count int // このページの件数
+	)
+	if q.query != "" {
+		if opts.OrderBy != store.OrderByDefault || opts.Descending {
+			return nil, invalidArgumentError("order_by", "must not be set together with query")
+		}
+		page = s.searchPage(q.query, filter, opts)
+		count = len(page.results)
+	} else {
+		albums, total, err := s.store.List(ctx, filter, opts)
+		if err != nil {
+			return nil, storeError(ctx, err, "")
+		}
+		page = &listPage{albums: albums, totalSize: total}
+		count = len(albums)
+	}
+
+	if next := opts.Offset + count; next < page.totalSize {
+		page.nextPageToken = encodePageToken(pageToken{Offset: next, Query: fingerprint})
+	}
+
+	return page, nil
+}
+
+// 全文検索の結果を関連度順に並べ、絞り込み条件に一致するものを1ページ分取り出すメソッド
+func (s *AlbumServer) searchPage(query string, filter store.Filter, opts store.ListOptions) *listPage {
+	var results []search.Result
+	for _, result := range s.index.Search(query) {
+		if filter.Match(result.Album) {
+			results = append(results, result)
+		}
+	}
+
+	page := &listPage{totalSize: len(results)}
+	if opts.Offset < len(results) {
+		page.results = results[opts.Offset:min(opts.Offset+opts.Limit, len(results))]
+	}
+
+	return page
+}
+
+// リクエストの絞り込み条件をストアの条件に変換する関数
+func toStoreFilter(f *pb.AlbumFilter) (store.Filter, error) {
+	if f == nil {
+		return store.Filter{}, nil
+	}
+
+	filter := store.Filter{
+		Title:      f.Title,
+		Artist:     f.Artist,
+		IgnoreCase: f.IgnoreCase,
+		MinPrice:   f.MinPrice,
+		MaxPrice:   f.MaxPrice,
+	}
+
+	switch f.MatchMode {
+	case pb.MatchMode_MATCH_MODE_EXACT:
+		filter.MatchMode = store.MatchExact
+	case pb.MatchMode_MATCH_MODE_CONTAINS:
+		filter.MatchMode = store.MatchContains
+	default:
+		return store.Filter{}, invalidArgumentError("filter.match_mode", fmt.Sprintf("unknown match mode: %d", f.MatchMode))
+	}
+
+	if f.MinPrice != nil {
+		if err := money.Validate(f.MinPrice); err != nil {
+			return store.Filter{}, invalidArgumentError("filter.min_price", err.Error())
+		}
+	}
+	if f.MaxPrice != nil {
+		if err := money.Validate(f.MaxPrice); err != nil {
+			return store.Filter{}, invalidArgumentError("filter.max_price", err.Error())
+		}
+	}
+
+	return filter, nil
+}
+
+// "price desc"のようなorder_byを並び順に変換する関数
+func parseOrderBy(orderBy string) (store.ListOptions, error) {
+	fields := strings.Fields(orderBy)
+	if len(fields) == 0 {
+		return store.ListOptions{}, nil
+	}
+
+	var opts store.ListOptions
+	switch fields[0] {
+	case "title":
+		opts.OrderBy = store.OrderByTitle
+	case "artist":
+		opts.OrderBy = store.OrderByArtist
+	case "price":
+		opts.OrderBy = store.OrderByPrice
+	default:
+		return opts, invalidArgumentError("order_by", fmt.Sprintf("unknown field: %q", fields[0]))
+	}
+
+	switch {
+	case len(fields) == 1:
+	case len(fields) == 2 && fields[1] == "asc":
+	case len(fields) == 2 && fields[1] == "desc":
+		opts.Descending = true
+	default:
+		return opts, invalidArgumentError("order_by", fmt.Sprintf("invalid format: %q", orderBy))
+	}
+
+	return opts, nil
+}
+
+// ページをまたいで変わってはいけない条件（絞り込み条件・並び順・キーワード）のハッシュを計算する関数
+// page_sizeはページごとに変えてよいため含めない
+func queryFingerprint(q listQuery) uint64 {
+	h := fnv.New64a()
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(q.filter)
+	h.Write(data)
+	h.Write([]byte(strings.Join(strings.Fields(q.orderBy), " ")))
+	h.Write([]byte{0})
+	h.Write([]byte(q.query))
+
+	return h.Sum64()
+}
+
+func encodePageToken(token pageToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(s string) (pageToken, error) {
+	var token pageToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return token, err
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return token, err
+	}
+	if token.Offset < 0 {
+		return token, fmt.Errorf("negative offset: %d", token.Offset)
+	}
+
+	return token, nil
+}
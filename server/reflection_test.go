@@ -0,0 +1,96 @@
+package main
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/store"
+	"context"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// grpcurlと同じようにリフレクションのクライアントでサービスの一覧と型の定義を取得できることを確認する
+func TestReflection(t *testing.T) {
+	grpcServer := grpc.NewServer()
+	pb.RegisterAlbumServiceServer(grpcServer, newTestAlbumServer(t, store.NewMemoryStore()))
+	reflection.Register(grpcServer)
+	conn := serveBufconn(t, grpcServer)
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.CloseSend()
+
+	request := func(req *reflectionpb.ServerReflectionRequest) *reflectionpb.ServerReflectionResponse {
+		t.Helper()
+		if err := stream.Send(req); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			t.Fatalf("reflection error: %s", e.ErrorMessage)
+		}
+		return resp
+	}
+
+	t.Run("ListServices", func(t *testing.T) {
+		resp := request(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+
+		var services []string
+		for _, s := range resp.GetListServicesResponse().GetService() {
+			services = append(services, s.Name)
+		}
+		for _, want := range []string{"album.AlbumService", "grpc.reflection.v1.ServerReflection"} {
+			if !slices.Contains(services, want) {
+				t.Errorf("services = %v, want %s", services, want)
+			}
+		}
+	})
+
+	t.Run("FileContainingSymbol", func(t *testing.T) {
+		resp := request(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "album.Album"},
+		})
+
+		// 定義を含むファイルと、そのファイルが依存するファイルが返る
+		var found *descriptorpb.DescriptorProto
+		for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(b, file); err != nil {
+				t.Fatal(err)
+			}
+			if file.GetPackage() != "album" {
+				continue
+			}
+			for _, m := range file.GetMessageType() {
+				if m.GetName() == "Album" {
+					found = m
+				}
+			}
+		}
+		if found == nil {
+			t.Fatal("album.Album was not described")
+		}
+
+		var fields []string
+		for _, f := range found.GetField() {
+			fields = append(fields, f.GetName())
+		}
+		for _, want := range []string{"id", "title", "artist", "price", "tracks"} {
+			if !slices.Contains(fields, want) {
+				t.Errorf("album.Album fields = %v, want %s", fields, want)
+			}
+		}
+	})
+}
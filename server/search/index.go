@@ -0,0 +1,283 @@
+package search
+
+import (
+	"awsomeProject/pb"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// フィールドごとの重み（タイトルやアーティストに一致したアルバムほど上位にする）
+const (
+	titleWeight  = 3.0
+	artistWeight = 2.0
+	genreWeight  = 1.0
+	trackWeight  = 0.5
+)
+
+// 単語の一致方法ごとの重み
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.7
+	fuzzyMatch  = 0.5 // 編集距離1の場合（距離が増えるごとに割り引く）
+)
+
+// 全文検索の結果
+type Result struct {
+	Album        *pb.Album
+	Score        float64  // 関連度（大きいほどクエリに近い）
+	MatchedTerms []string // クエリに一致した索引の単語
+}
+
+// アルバムの全文検索のための転置インデックス
+// 複数のgoroutineから同時に呼び出しても安全
+type Index struct {
+	mu       sync.RWMutex
+	albums   map[string]*pb.Album          // IDごとの索引に登録したアルバム
+	postings map[string]map[string]float64 // 単語ごとの、その単語を含むアルバムのIDと重み
+	terms    []string                      // 前方一致の検索に使う、ソート済みの単語の一覧
+}
+
+func NewIndex() *Index {
+	return &Index{
+		albums:   make(map[string]*pb.Album),
+		postings: make(map[string]map[string]float64),
+	}
+}
+
+// アルバムを索引に登録するメソッド
+// 同じIDのアルバムが登録済みの場合は置き換える
+func (ix *Index) Add(album *pb.Album) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(album.Id)
+
+	ix.albums[album.Id] = proto.Clone(album).(*pb.Album)
+	for term, weight := range documentTerms(album) {
+		docs, ok := ix.postings[term]
+		if !ok {
+			docs = make(map[string]float64)
+			ix.postings[term] = docs
+			i, _ := slices.BinarySearch(ix.terms, term)
+			ix.terms = slices.Insert(ix.terms, i, term)
+		}
+		docs[album.Id] = weight
+	}
+}
+
+// アルバムを索引から削除するメソッド
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+// 呼び出し元でロックを取得しておくこと
+func (ix *Index) remove(id string) {
+	album, ok := ix.albums[id]
+	if !ok {
+		return
+	}
+
+	delete(ix.albums, id)
+	for term := range documentTerms(album) {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+			if i, found := slices.BinarySearch(ix.terms, term); found {
+				ix.terms = slices.Delete(ix.terms, i, i+1)
+			}
+		}
+	}
+}
+
+// クエリに一致するアルバムを関連度の高い順に返すメソッド
+//
+// クエリの単語ごとに、索引の単語と完全一致・前方一致・編集距離による曖昧一致のいずれかで照合し、
+// フィールドの重みと単語の珍しさ（IDF）を掛けたものを関連度とする
+// クエリの単語の一部にしか一致しないアルバムは、一致した割合に応じて関連度を下げる
+func (ix *Index) Search(query string) []Result {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	type hit struct {
+		score   float64
+		matched int // 一致したクエリの単語の数
+		terms   []string
+	}
+	hits := make(map[string]*hit)
+	n := float64(len(ix.albums))
+
+	for _, q := range queryTerms {
+		// クエリの単語ごとに、アルバムごとの最も高いスコアだけを採用する
+		best := make(map[string]float64)
+		bestTerm := make(map[string]string)
+		for term, match := range ix.candidates(q) {
+			docs := ix.postings[term]
+			idf := math.Log(1 + n/float64(len(docs)))
+			for id, weight := range docs {
+				if score := weight * match * idf; score > best[id] {
+					best[id] = score
+					bestTerm[id] = term
+				}
+			}
+		}
+
+		for id, score := range best {
+			h, ok := hits[id]
+			if !ok {
+				h = &hit{}
+				hits[id] = h
+			}
+			h.score += score
+			h.matched++
+			if !slices.Contains(h.terms, bestTerm[id]) {
+				h.terms = append(h.terms, bestTerm[id])
+			}
+		}
+	}
+
+	results := make([]Result, 0, len(hits))
+	for id, h := range hits {
+		coverage := float64(h.matched) / float64(len(queryTerms))
+		results = append(results, Result{
+			Album:        proto.Clone(ix.albums[id]).(*pb.Album),
+			Score:        h.score * coverage * coverage,
+			MatchedTerms: h.terms,
+		})
+	}
+
+	// 関連度が同じ場合はタイトル、IDの順に並べて結果を安定させる
+	slices.SortFunc(results, func(a, b Result) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		if c := strings.Compare(a.Album.Title, b.Album.Title); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Album.Id, b.Album.Id)
+	})
+
+	return results
+}
+
+// クエリの単語に一致する索引の単語と、一致方法の重みを返すメソッド
+// 呼び出し元で読み取りロックを取得しておくこと
+func (ix *Index) candidates(q string) map[string]float64 {
+	matches := make(map[string]float64)
+
+	// 前方一致（完全一致を含む）はソート済みの単語一覧を二分探索して探す
+	i, _ := slices.BinarySearch(ix.terms, q)
+	for ; i < len(ix.terms) && strings.HasPrefix(ix.terms[i], q); i++ {
+		if ix.terms[i] == q {
+			matches[q] = exactMatch
+		} else {
+			// 補完される文字数が多いほど重みを下げる
+			matches[ix.terms[i]] = prefixMatch * float64(len(q)) / float64(len(ix.terms[i]))
+		}
+	}
+
+	// 短い単語は曖昧一致させると無関係な単語に一致しやすいため対象外にする
+	maxDist := maxEditDistance(q)
+	if maxDist == 0 {
+		return matches
+	}
+	for _, term := range ix.terms {
+		if _, ok := matches[term]; ok {
+			continue
+		}
+		if d := editDistance(q, term, maxDist); d <= maxDist {
+			matches[term] = fuzzyMatch / float64(d)
+		}
+	}
+
+	return matches
+}
+
+// アルバムから索引に登録する単語とその重みを取り出す関数
+// 同じ単語が複数のフィールドに含まれる場合は、最も大きい重みを使う
+func documentTerms(album *pb.Album) map[string]float64 {
+	terms := make(map[string]float64)
+	add := func(text string, weight float64) {
+		for _, term := range tokenize(text) {
+			terms[term] = max(terms[term], weight)
+		}
+	}
+
+	add(album.Title, titleWeight)
+	add(album.Artist, artistWeight)
+	add(album.Genre, genreWeight)
+	for _, track := range album.Tracks {
+		add(track.Title, trackWeight)
+	}
+
+	return terms
+}
+
+// 文字列を小文字の単語に分割する関数
+// 文字と数字以外（空白や記号）を区切りとして扱う
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// 曖昧一致で許容する編集距離を返す関数
+func maxEditDistance(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// 2つの単語の編集距離（レーベンシュタイン距離）を計算する関数
+// limitを超えることが確定した時点で計算を打ち切り、limit+1を返す
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
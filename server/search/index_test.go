@@ -0,0 +1,129 @@
+package search
+
+import (
+	"awsomeProject/pb"
+	"slices"
+	"testing"
+)
+
+func testIndex() *Index {
+	ix := NewIndex()
+	for _, album := range []*pb.Album{
+		{Id: "a1", Title: "Blue Train", Artist: "John Coltrane", Genre: "Jazz"},
+		{Id: "a2", Title: "Kind of Blue", Artist: "Miles Davis", Genre: "Jazz"},
+		{Id: "a3", Title: "Moanin'", Artist: "Art Blakey", Genre: "Hard Bop", Tracks: []*pb.Track{{Number: 1, Title: "Blues March"}}},
+		{Id: "a4", Title: "Time Out", Artist: "Dave Brubeck", Genre: "Cool Jazz"},
+	} {
+		ix.Add(album)
+	}
+
+	return ix
+}
+
+func resultIDs(results []Result) []string {
+	ids := make([]string, len(results))
+	for i, r := range results {
+		ids[i] = r.Album.Id
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := testIndex()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    []string // 関連度の高い順のID
+		matched []string // 先頭の結果で一致した索引の単語
+	}{
+		{name: "exact", query: "coltrane", want: []string{"a1"}, matched: []string{"coltrane"}},
+		{name: "case insensitive", query: "COLTRANE", want: []string{"a1"}, matched: []string{"coltrane"}},
+		{name: "prefix", query: "colt", want: []string{"a1"}, matched: []string{"coltrane"}},
+		{name: "fuzzy", query: "brubek", want: []string{"a4"}, matched: []string{"brubeck"}},
+		{name: "fuzzy within two edits for long terms", query: "coltrain", want: []string{"a1"}, matched: []string{"coltrane"}},
+		{name: "no fuzzy match for short terms", query: "jaz", want: []string{"a1", "a2", "a4"}, matched: []string{"jazz"}},
+		// タイトルの完全一致は同点のためタイトル順になり、曲名の前方一致はその後になる
+		{name: "exact before prefix", query: "blue", want: []string{"a1", "a2", "a3"}, matched: []string{"blue"}},
+		// すべての単語に一致したアルバムが、一部の単語にだけ一致したアルバムより上位になる
+		{name: "coverage", query: "kind blue", want: []string{"a2", "a1", "a3"}, matched: []string{"kind", "blue"}},
+		{name: "no match", query: "zzzz"},
+		{name: "empty query", query: " !? "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := ix.Search(tt.query)
+			if got := resultIDs(results); !slices.Equal(got, tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			if len(results) == 0 {
+				return
+			}
+			if !slices.Equal(results[0].MatchedTerms, tt.matched) {
+				t.Errorf("matched terms = %v, want %v", results[0].MatchedTerms, tt.matched)
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("results are not sorted by score: %v", results)
+				}
+			}
+		})
+	}
+}
+
+// 一致方法とフィールドの重みが関連度に反映されることを確認する
+func TestIndexScoring(t *testing.T) {
+	ix := testIndex()
+	score := func(query, id string) float64 {
+		t.Helper()
+		for _, r := range ix.Search(query) {
+			if r.Album.Id == id {
+				return r.Score
+			}
+		}
+		t.Fatalf("Search(%q) did not return %s", query, id)
+		return 0
+	}
+
+	exact, prefix, fuzzy := score("coltrane", "a1"), score("coltra", "a1"), score("coltrana", "a1")
+	if !(exact > prefix && prefix > fuzzy) {
+		t.Errorf("scores exact = %v, prefix = %v, fuzzy = %v, want exact > prefix > fuzzy", exact, prefix, fuzzy)
+	}
+
+	// 同じ単語でも、タイトルに含むアルバムは曲名に含むアルバムより上位になる
+	ix.Add(&pb.Album{Id: "a5", Title: "Blues Etude", Artist: "Oscar Peterson"})
+	if title, track := score("blues", "a5"), score("blues", "a3"); title <= track {
+		t.Errorf("title score = %v, track score = %v, want title > track", title, track)
+	}
+}
+
+func TestIndexAddAndRemove(t *testing.T) {
+	ix := testIndex()
+
+	// 同じIDで登録し直すと、古い内容の単語では見つからなくなる
+	ix.Add(&pb.Album{Id: "a1", Title: "Giant Steps", Artist: "John Coltrane"})
+	if got := resultIDs(ix.Search("train")); len(got) != 0 {
+		t.Errorf("Search(train) after update = %v, want none", got)
+	}
+	if got := resultIDs(ix.Search("giant")); !slices.Equal(got, []string{"a1"}) {
+		t.Errorf("Search(giant) after update = %v, want [a1]", got)
+	}
+
+	ix.Remove("a1")
+	for _, query := range []string{"giant", "coltrane", "colt"} {
+		if got := resultIDs(ix.Search(query)); len(got) != 0 {
+			t.Errorf("Search(%q) after Remove = %v, want none", query, got)
+		}
+	}
+	// どのアルバムにも含まれなくなった単語は単語の一覧からも消える
+	if _, found := slices.BinarySearch(ix.terms, "coltrane"); found {
+		t.Error("removed term is still in the term list")
+	}
+	// 他のアルバムと共有している単語は残る
+	if got := resultIDs(ix.Search("jazz")); !slices.Equal(got, []string{"a2", "a4"}) {
+		t.Errorf("Search(jazz) after Remove = %v, want [a2 a4]", got)
+	}
+
+	// 存在しないIDの削除は何もしない
+	ix.Remove("unknown")
+}
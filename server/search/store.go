@@ -0,0 +1,77 @@
+package search
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/store"
+	"context"
+	"sync"
+)
+
+// 変更を全文検索の索引にも反映するAlbumStore
+// 元のストアへの書き込みと索引の更新を1つの操作として直列化するため、
+// 書き込みが完了した時点の索引はストアの内容と一致している
+type IndexedStore struct {
+	store.AlbumStore
+
+	// ストアへの書き込みと索引の更新の間に別の書き込みが割り込むと、
+	// 古い内容で索引を上書きしたり、削除したアルバムを索引に戻したりするため、両方をまとめてロックする
+	writeMu sync.Mutex
+	index   *Index
+}
+
+// ストアのすべてのアルバムから索引を作成し、IndexedStoreを作成する
+func NewIndexedStore(ctx context.Context, s store.AlbumStore) (*IndexedStore, error) {
+	index := NewIndex()
+	err := s.Iterate(ctx, func(album *pb.Album) bool {
+		index.Add(album)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndexedStore{AlbumStore: s, index: index}, nil
+}
+
+// 全文検索の索引を返すメソッド
+func (s *IndexedStore) Index() *Index {
+	return s.index
+}
+
+func (s *IndexedStore) Insert(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	album, err := s.AlbumStore.Insert(ctx, album)
+	if err != nil {
+		return nil, err
+	}
+
+	s.index.Add(album)
+	return album, nil
+}
+
+func (s *IndexedStore) Update(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	album, err := s.AlbumStore.Update(ctx, album)
+	if err != nil {
+		return nil, err
+	}
+
+	s.index.Add(album)
+	return album, nil
+}
+
+func (s *IndexedStore) Delete(ctx context.Context, id string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if err := s.AlbumStore.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.index.Remove(id)
+	return nil
+}
@@ -0,0 +1,117 @@
+package search
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/store"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// 書き込みが完了してから呼び出し元に戻るまでに時間がかかるストア
+// 索引の更新が別の書き込みと入れ替わる状況を起こしやすくする
+type slowStore struct {
+	store.AlbumStore
+}
+
+func (s slowStore) Update(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	album, err := s.AlbumStore.Update(ctx, album)
+	time.Sleep(time.Duration(rand.IntN(100)) * time.Microsecond)
+	return album, err
+}
+
+func (s slowStore) Delete(ctx context.Context, id string) error {
+	err := s.AlbumStore.Delete(ctx, id)
+	time.Sleep(time.Duration(rand.IntN(100)) * time.Microsecond)
+	return err
+}
+
+// 同じアルバムへの更新と削除を並行に行っても、索引がストアの内容と一致することを確認する
+// go test -raceで実行すると、索引の更新のデータ競合も検出できる
+func TestIndexedStoreConcurrentWrites(t *testing.T) {
+	const (
+		albums  = 8
+		writers = 4
+		updates = 20
+	)
+
+	ctx := context.Background()
+	s, err := NewIndexedStore(ctx, slowStore{store.NewMemoryStore()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, albums)
+	for i := range ids {
+		album, err := s.Insert(ctx, &pb.Album{Title: fmt.Sprintf("Album %d", i), Artist: "Test Artist"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = album.Id
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, albums*(writers+1))
+	for i, id := range ids {
+		for w := range writers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for n := range updates {
+					album := &pb.Album{Id: id, Title: fmt.Sprintf("Album %d writer %d update %d", i, w, n), Artist: "Test Artist"}
+					if _, err := s.Update(ctx, album); err != nil && !errors.Is(err, store.ErrNotFound) {
+						errs <- err
+						return
+					}
+				}
+			}()
+		}
+
+		// 半分のアルバムは更新と並行に削除する
+		if i%2 == 1 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.Delete(ctx, id); err != nil {
+					errs <- err
+				}
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// 索引に登録されたアルバムが、ストアのアルバムと過不足なく一致する
+	stored, _, err := s.List(ctx, store.Filter{}, store.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != albums/2 {
+		t.Errorf("albums in store = %d, want %d", len(stored), albums/2)
+	}
+
+	s.index.mu.RLock()
+	defer s.index.mu.RUnlock()
+	if len(s.index.albums) != len(stored) {
+		t.Errorf("albums in index = %d, want %d", len(s.index.albums), len(stored))
+	}
+	for _, album := range stored {
+		indexed, ok := s.index.albums[album.Id]
+		if !ok {
+			t.Errorf("album %s is not in the index", album.Id)
+			continue
+		}
+		if !proto.Equal(indexed, album) {
+			t.Errorf("index has a stale album: got %v, want %v", indexed, album)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// 停止のシグナルを受け取るまでgRPCサーバーを動かす関数
+//
+// シグナルを受け取るとヘルスチェックをNOT_SERVINGにして新しいRPCの受け付けを止め、
+// 実行中のRPCが終わるのをdrainTimeoutまで待つ
+// 時間内に終わらない場合や、待っている間に再度シグナルを受け取った場合は、残りの接続を強制的に閉じる
+func serve(grpcServer *grpc.Server, lis net.Listener, health *healthChecker, signals <-chan os.Signal, drainTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis) // grpcServerを起動
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case sig := <-signals:
+		slog.Info("draining connections", "signal", sig.String(), "timeout", drainTimeout)
+	}
+	health.shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(drainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		slog.Info("all RPCs finished")
+	case <-timer.C:
+		slog.Warn("drain timeout exceeded, closing remaining connections")
+		grpcServer.Stop()
+	case sig := <-signals:
+		slog.Warn("received signal again, closing remaining connections", "signal", sig.String())
+		grpcServer.Stop()
+	}
+	<-stopped
+
+	return <-serveErr
+}
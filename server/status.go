@@ -0,0 +1,61 @@
+package main
+
+import (
+	"awsomeProject/server/store"
+	"context"
+	"errors"
+	"log/slog"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const albumResourceType = "album.Album" // ResourceInfoに設定するリソースの種類
+
+// リクエストのフィールドが不正な場合のエラーを作成する関数
+// BadRequestのフィールド違反として、どのフィールドがなぜ不正なのかをクライアントに伝える
+func invalidArgumentError(field, description string) error {
+	return withDetails(
+		status.New(codes.InvalidArgument, "invalid "+field+": "+description),
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: field, Description: description},
+			},
+		},
+	)
+}
+
+// ストアのエラーをgRPCのステータスに変換する関数
+// nameには対象のアルバムを識別する値（IDまたはタイトル）を渡す
+func storeError(ctx context.Context, err error, name string) error {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return withDetails(
+			status.New(codes.NotFound, "album not found: "+name),
+			&errdetails.ResourceInfo{ResourceType: albumResourceType, ResourceName: name, Description: "album does not exist"},
+		)
+	case errors.Is(err, store.ErrAlreadyExists):
+		return withDetails(
+			status.New(codes.AlreadyExists, "album already exists: "+name),
+			&errdetails.ResourceInfo{ResourceType: albumResourceType, ResourceName: name, Description: "album with the same title and artist already exists"},
+		)
+	default:
+		// 内部のエラー内容はクライアントに返さずログにだけ残す
+		slog.ErrorContext(ctx, "store error", "error", err)
+		return status.Error(codes.Internal, "failed to access album storage")
+	}
+}
+
+// ステータスに詳細情報を付与してエラーとして返す関数
+// 詳細情報の付与に失敗した場合は、詳細なしのステータスを返す
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		slog.Error("failed to attach error details", "error", err)
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
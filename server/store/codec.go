@@ -0,0 +1,142 @@
+package store
+
+import (
+	"awsomeProject/money"
+	"awsomeProject/pb"
+	"bytes"
+	"encoding/json"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// タイムスタンプなどのWell-Known Typesを正しく扱うため、アルバムのJSON変換にはprotojsonを使う
+// フィールド名は既存のJSONファイルに合わせて.protoの名前（snake_case）のまま出力する
+var (
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// アルバムのリストをインデント付きのJSON配列に変換する関数
+func marshalAlbums(albums []*pb.Album) ([]byte, error) {
+	raws := make([]json.RawMessage, 0, len(albums))
+	for _, album := range albums {
+		raw, err := marshalOptions.Marshal(album)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+
+	data, err := json.Marshal(raws)
+	if err != nil {
+		return nil, err
+	}
+
+	// protojsonの出力は空白が安定しないため、配列全体をまとめて整形する
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// JSON配列をアルバムのリストに変換する関数
+// 古い形式の価格を変換したアルバムがあった場合はupgradedにtrueを返す
+func unmarshalAlbums(data []byte) (albums []*pb.Album, upgraded bool, err error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, false, err
+	}
+
+	albums = make([]*pb.Album, 0, len(raws))
+	for _, raw := range raws {
+		album, legacy, err := unmarshalAlbum(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		albums = append(albums, album)
+		upgraded = upgraded || legacy
+	}
+
+	return albums, upgraded, nil
+}
+
+// JSONをアルバムに変換する関数
+// priceが数値（floatだった頃の形式）の場合は、DefaultCurrencyのMoneyとして読み込みlegacyにtrueを返す
+func unmarshalAlbum(data []byte) (album *pb.Album, legacy bool, err error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, false, err
+	}
+
+	if price, ok := fields["price"]; ok && len(price) > 0 && price[0] != '{' && price[0] != 'n' {
+		// JSONの数値の文字列をそのまま10進数として読むことで、floatの誤差を持ち込まない
+		m, err := money.Parse(DefaultCurrency, string(price))
+		if err != nil {
+			return nil, false, err
+		}
+		if fields["price"], err = marshalOptions.Marshal(m); err != nil {
+			return nil, false, err
+		}
+		if data, err = json.Marshal(fields); err != nil {
+			return nil, false, err
+		}
+		legacy = true
+	}
+
+	album = &pb.Album{}
+	if err := unmarshalOptions.Unmarshal(data, album); err != nil {
+		return nil, false, err
+	}
+
+	return album, legacy, nil
+}
+
+// protojsonで変換するための、アルバムをJSONに埋め込むラッパー
+// ジャーナルのエントリなど、encoding/jsonで扱う構造体の中でアルバムを保持するときに使う
+type jsonAlbum struct {
+	*pb.Album
+}
+
+func (a jsonAlbum) MarshalJSON() ([]byte, error) {
+	return marshalOptions.Marshal(a.Album)
+}
+
+func (a *jsonAlbum) UnmarshalJSON(data []byte) (err error) {
+	a.Album, _, err = unmarshalAlbum(data)
+	return err
+}
+
+// 曲のリストをJSON配列に変換する関数
+func marshalTracks(tracks []*pb.Track) ([]byte, error) {
+	raws := make([]json.RawMessage, 0, len(tracks))
+	for _, track := range tracks {
+		raw, err := marshalOptions.Marshal(track)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+
+	return json.Marshal(raws)
+}
+
+// JSON配列を曲のリストに変換する関数
+func unmarshalTracks(data []byte) ([]*pb.Track, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, err
+	}
+
+	tracks := make([]*pb.Track, 0, len(raws))
+	for _, raw := range raws {
+		track := &pb.Track{}
+		if err := unmarshalOptions.Unmarshal(raw, track); err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// JSONファイルのアルバムデータを別のストアに取り込む関数
+// 既に存在するアルバムは上書きせずにスキップし、取り込んだ件数を返す
+// IDや作成日時を持つアルバムはその値のまま取り込む
+func ImportJSON(ctx context.Context, dst AlbumStore, filePath string) (int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	albums, _, err := unmarshalAlbums(data)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	for _, album := range albums {
+		_, err := dst.Insert(ctx, album)
+		if errors.Is(err, ErrAlreadyExists) {
+			continue
+		}
+		if err != nil {
+			return imported, err
+		}
+		imported++
+	}
+
+	return imported, nil
+}
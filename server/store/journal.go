@@ -0,0 +1,159 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ジャーナルに記録する操作の種類
+const (
+	opInsert = "insert"
+	opUpdate = "update"
+	opDelete = "delete"
+)
+
+// ジャーナルの1行分のエントリ
+type journalEntry struct {
+	Op    string     `json:"op"`
+	Album *jsonAlbum `json:"album,omitempty"` // insert, updateの対象
+	ID    string     `json:"id,omitempty"`    // deleteの対象
+}
+
+// 本体ファイルへの書き込み前に操作を記録するwrite-aheadジャーナル
+// 本体ファイルの書き込み中にクラッシュしても、起動時にジャーナルを再生すれば変更を失わない
+type journal struct {
+	filePath string
+}
+
+// エントリをジャーナルの末尾に追記し、ディスクに書き出すまで待つメソッド
+func (j *journal) append(entry journalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(j.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ジャーナルに記録されたエントリをすべて読み込むメソッド
+// 追記途中でクラッシュした末尾の不完全な行は、確定していない操作として読み飛ばす
+func (j *journal) entries() ([]journalEntry, error) {
+	data, err := os.ReadFile(j.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	// 改行で終わっていない末尾の行は書きかけのため捨てる（改行で終わる場合は空行になる）
+	lines = lines[:len(lines)-1]
+
+	var entries []journalEntry
+	for i, line := range lines {
+		var entry journalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("journal %s line %d: %w", j.filePath, i+1, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// 本体ファイルへの反映が済んだエントリを破棄するメソッド
+func (j *journal) reset() error {
+	err := os.Remove(j.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// ジャーナルのエントリをメモリ上のデータに適用するメソッド
+// 記録されたアルバムはIDや日時を設定済みのため、値を変えずにそのまま反映する
+// 本体ファイルに反映済みのエントリが再生されることもあるため、重複や欠落によるエラーは無視する
+func (e journalEntry) apply(ctx context.Context, m *MemoryStore) error {
+	var err error
+	switch e.Op {
+	case opInsert:
+		if err = m.put(clone(e.Album.Album)); errors.Is(err, ErrAlreadyExists) {
+			err = nil
+		}
+	case opUpdate:
+		if err = m.replace(clone(e.Album.Album)); errors.Is(err, ErrNotFound) {
+			err = nil
+		}
+	case opDelete:
+		if err = m.Delete(ctx, e.ID); errors.Is(err, ErrNotFound) {
+			err = nil
+		}
+	default:
+		err = fmt.Errorf("unknown journal operation: %q", e.Op)
+	}
+
+	return err
+}
+
+// 一時ファイルに書き込んでからリネームすることで、ファイルをアトミックに置き換える関数
+// 途中でクラッシュしても元のファイルか新しいファイルのどちらかが必ず残る
+func writeFileAtomic(filePath string, data []byte) error {
+	dir := filepath.Dir(filePath)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// リネームに成功した後は一時ファイルが存在しないため、Removeは何もしない
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	// リネーム前に内容をディスクに書き出しておく
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return err
+	}
+
+	// リネーム結果を永続化するため、ディレクトリもfsyncする
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
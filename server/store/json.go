@@ -0,0 +1,216 @@
+package store
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JSONファイルにアルバムを保存するAlbumStoreの実装
+// 読み取りはメモリ上のデータに対して行い、変更があるたびにファイル全体を書き直す
+//
+// 変更はまずジャーナルに記録してからファイルに反映するため、
+// 書き込み中にクラッシュしても次回起動時にジャーナルから復元される
+//
+// ジャーナルへの記録が成功した時点で変更は確定したものとして扱う
+// その後のファイルへの保存に失敗しても変更は取り消さず、次の変更やClose、次回起動時のジャーナルの再生でファイルに反映する
+type JSONStore struct {
+	*MemoryStore
+
+	writeMu  sync.Mutex // メモリ上の変更とファイルへの書き込みを1つの操作として直列化する
+	filePath string     // アルバムデータを保存するJSONファイルのパス
+	journal  *journal   // ファイルに未反映の変更を記録するジャーナル
+}
+
+// JSONファイルからアルバムデータをロードしてJSONStoreを作成する
+func NewJSONStore(filePath string) (*JSONStore, error) {
+	s := &JSONStore{
+		MemoryStore: NewMemoryStore(),
+		filePath:    filePath,
+		journal:     &journal{filePath: filePath + ".journal"},
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ジャーナルには日時やIDを設定した後のアルバムを記録し、再生しても同じ結果になるようにする
+// 変更はwriteMuで直列化されているため、事前チェックの結果は書き込みまで変わらない
+func (s *JSONStore) Insert(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	album = clone(album)
+	prepareInsert(album, time.Now())
+
+	s.mu.RLock()
+	err := s.validateInsert(album)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commit(ctx, journalEntry{Op: opInsert, Album: &jsonAlbum{album}}); err != nil {
+		return nil, err
+	}
+
+	return clone(album), nil
+}
+
+func (s *JSONStore) Update(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.MemoryStore.Get(ctx, album.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	album = clone(album)
+	prepareUpdate(album, current, time.Now())
+
+	s.mu.RLock()
+	_, err = s.validateReplace(album)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commit(ctx, journalEntry{Op: opUpdate, Album: &jsonAlbum{album}}); err != nil {
+		return nil, err
+	}
+
+	return clone(album), nil
+}
+
+func (s *JSONStore) Delete(ctx context.Context, id string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, err := s.MemoryStore.Get(ctx, id); err != nil {
+		return err
+	}
+
+	return s.commit(ctx, journalEntry{Op: opDelete, ID: id})
+}
+
+// 変更をジャーナルに記録し、メモリ上のデータとファイルに反映するメソッド
+// エラーを返すのはジャーナルへの記録に失敗した（変更が確定していない）場合だけで、そのときはメモリ上のデータも変更しない
+// 呼び出し元でwriteMuを取得しておくこと
+func (s *JSONStore) commit(ctx context.Context, entry journalEntry) error {
+	if err := s.journal.append(entry); err != nil {
+		return err
+	}
+
+	// 事前に確認済みのため、ここでエラーになるのは不明な操作を記録した場合だけ
+	if err := entry.apply(ctx, s.MemoryStore); err != nil {
+		return err
+	}
+
+	// ジャーナルに記録済みのため、保存に失敗しても変更は失われない
+	// ジャーナルは残しておき、次の変更かCloseでの保存、または次回起動時の再生でファイルに反映する
+	if err := s.save(); err != nil {
+		slog.WarnContext(ctx, "failed to save albums, changes are kept in the journal", "path", s.filePath, "error", err)
+		return nil
+	}
+	if err := s.journal.reset(); err != nil {
+		// ファイルに反映済みのエントリは再生しても結果が変わらないため、残っていても問題ない
+		slog.WarnContext(ctx, "failed to reset journal", "path", s.journal.filePath, "error", err)
+	}
+
+	return nil
+}
+
+// ファイルを保存するディレクトリに書き込めるか確認するメソッド
+// 保存時と同じように一時ファイルを作成し、すぐに削除する
+func (s *JSONStore) Ping(ctx context.Context) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.filePath), "."+filepath.Base(s.filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+
+	return os.Remove(tmp.Name())
+}
+
+// 実行中の書き込みが終わるのを待ってからストアを閉じるメソッド
+// ファイルへの保存に失敗してジャーナルにだけ残っている変更があれば、ここでファイルに書き出す
+func (s *JSONStore) Close() error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	entries, err := s.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// メモリ上のデータにはジャーナルの変更が反映済みのため、そのまま保存する
+	if err := s.save(); err != nil {
+		return err
+	}
+
+	return s.journal.reset()
+}
+
+// ファイルからアルバムデータを読み込み、未反映のジャーナルがあれば再生するメソッド
+// IDや作成日時を持たない、または価格がfloatの古い形式のデータは、読み込み時に変換してファイルに書き戻す
+func (s *JSONStore) load() error {
+	data, err := os.ReadFile(s.filePath) // ファイルからアルバム情報を読み取る
+	if err != nil {
+		return err
+	}
+
+	// JSONデータをGoの構造体に変換しメモリ上に保持する
+	albums, migrated, err := unmarshalAlbums(data)
+	if err != nil {
+		return err
+	}
+
+	for _, album := range albums {
+		if album.Id == "" || album.CreatedAt == nil {
+			migrated = true
+		}
+	}
+	s.MemoryStore = NewMemoryStore(albums...)
+
+	entries, err := s.journal.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 && !migrated {
+		return nil
+	}
+
+	// 前回の書き込みが完了しなかった変更を反映し、ファイルに書き戻す
+	for _, entry := range entries {
+		if err := entry.apply(context.Background(), s.MemoryStore); err != nil {
+			return err
+		}
+	}
+	if err := s.save(); err != nil {
+		return err
+	}
+
+	return s.journal.reset()
+}
+
+// メモリ上のアルバムデータをファイルに書き込むメソッド
+func (s *JSONStore) save() error {
+	// albumリストをjson形式に変換
+	newData, err := marshalAlbums(s.snapshot())
+	if err != nil {
+		return err
+	}
+
+	// 書き込み途中でファイルが壊れないよう、一時ファイル経由で置き換える
+	return writeFileAtomic(s.filePath, newData)
+}
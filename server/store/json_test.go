@@ -0,0 +1,96 @@
+package store
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ファイルへの保存に失敗しても、ジャーナルに記録できた変更は成功として扱い、Closeでファイルに反映する
+func TestJSONStoreCommitsOnJournalAppend(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "album.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewJSONStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 空でないディレクトリに置き換え、リネームによる保存を失敗させる
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "blocker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	album, err := s.Insert(ctx, &pb.Album{Title: "Blue Train", Artist: "John Coltrane"})
+	if err != nil {
+		t.Fatalf("Insert() error = %v, want nil after journal append", err)
+	}
+	if _, err := s.Get(ctx, album.Id); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	entries, err := s.journal.entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("journal has %d entries, want 1", len(entries))
+	}
+
+	// 保存できる状態に戻すと、Closeでジャーナルの変更がファイルに書き出される
+	if err := os.RemoveAll(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(s.journal.filePath); !os.IsNotExist(err) {
+		t.Errorf("journal still exists after Close: %v", err)
+	}
+
+	reopened, err := NewJSONStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	got, err := reopened.Get(ctx, album.Id)
+	if err != nil {
+		t.Fatalf("album was not persisted: %v", err)
+	}
+	if got.Title != album.Title {
+		t.Errorf("title = %q, want %q", got.Title, album.Title)
+	}
+}
+
+// ジャーナルに記録できなかった変更はメモリ上のデータにも反映しない
+func TestJSONStoreRejectsWhenJournalFails(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "album.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewJSONStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// ジャーナルのパスをディレクトリにして追記を失敗させる
+	if err := os.Mkdir(s.journal.filePath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(s.journal.filePath)
+
+	if _, err := s.Insert(ctx, &pb.Album{Title: "Blue Train", Artist: "John Coltrane"}); err == nil {
+		t.Fatal("Insert() error = nil, want journal error")
+	}
+	if _, total, _ := s.List(ctx, Filter{}, ListOptions{}); total != 0 {
+		t.Errorf("albums in memory = %d, want 0", total)
+	}
+}
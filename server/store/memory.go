@@ -0,0 +1,207 @@
+package store
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// メモリ上にアルバムを保持するAlbumStoreの実装
+// 永続化が不要なテストや、他の実装の土台として使う
+//
+// 複数のgoroutineから同時に呼び出しても安全で、呼び出し元に返すアルバムは常にコピーのため
+// 返り値を書き換えても保存済みのデータには影響しない
+type MemoryStore struct {
+	mu     sync.RWMutex
+	albums []*pb.Album
+}
+
+// IDや作成日時が設定されていないアルバムには、追加時と同様に値を設定する
+func NewMemoryStore(albums ...*pb.Album) *MemoryStore {
+	m := &MemoryStore{}
+	now := time.Now()
+	for _, album := range albums {
+		album = clone(album)
+		prepareInsert(album, now)
+		m.albums = append(m.albums, album)
+	}
+
+	return m
+}
+
+func (m *MemoryStore) Get(ctx context.Context, id string) (*pb.Album, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if i := m.index(id); i >= 0 {
+		return clone(m.albums[i]), nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (m *MemoryStore) List(ctx context.Context, filter Filter, opts ListOptions) ([]*pb.Album, int, error) {
+	var albums []*pb.Album
+	for _, album := range m.snapshot() {
+		if filter.Match(album) {
+			albums = append(albums, album)
+		}
+	}
+	total := len(albums)
+
+	// 取得する範囲だけをコピーして返す
+	albums = opts.apply(albums)
+	for i, album := range albums {
+		albums[i] = clone(album)
+	}
+
+	return albums, total, nil
+}
+
+func (m *MemoryStore) Insert(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	album = clone(album)
+	prepareInsert(album, time.Now())
+	if err := m.put(album); err != nil {
+		return nil, err
+	}
+
+	return clone(album), nil
+}
+
+func (m *MemoryStore) Update(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	current, err := m.Get(ctx, album.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	album = clone(album)
+	prepareUpdate(album, current, time.Now())
+	if err := m.replace(album); err != nil {
+		return nil, err
+	}
+
+	return clone(album), nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.index(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+
+	// 元のスライスを共有しているスナップショットを壊さないよう、新しいスライスを作る
+	m.albums = slices.Delete(slices.Clone(m.albums), i, i+1)
+	return nil
+}
+
+// ロック中にfnを呼ぶとfn内でのストア操作がデッドロックするため、
+// 呼び出し時点のスナップショットに対して処理する
+func (m *MemoryStore) Iterate(ctx context.Context, fn func(*pb.Album) bool) error {
+	for _, album := range m.snapshot() {
+		if !fn(clone(album)) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// メモリ上のデータは常に読み書きできる
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// IDと日時を設定済みのアルバムをそのまま追加するメソッド
+// 値を変えずに反映する必要があるジャーナルの再生でも使う
+func (m *MemoryStore) put(album *pb.Album) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.validateInsert(album); err != nil {
+		return err
+	}
+
+	m.albums = append(m.albums, album)
+	return nil
+}
+
+// IDと日時を設定済みのアルバムで、同じIDのアルバムをそのまま置き換えるメソッド
+func (m *MemoryStore) replace(album *pb.Album) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i, err := m.validateReplace(album)
+	if err != nil {
+		return err
+	}
+
+	m.albums[i] = album
+	return nil
+}
+
+// アルバムを追加できるか確認するメソッド
+// 呼び出し元でロックを取得しておくこと
+func (m *MemoryStore) validateInsert(album *pb.Album) error {
+	if m.index(album.Id) >= 0 || m.conflicts(album) {
+		return ErrAlreadyExists
+	}
+
+	return nil
+}
+
+// アルバムを置き換えられるか確認し、置き換える位置を返すメソッド
+// 呼び出し元でロックを取得しておくこと
+func (m *MemoryStore) validateReplace(album *pb.Album) (int, error) {
+	i := m.index(album.Id)
+	if i < 0 {
+		return -1, ErrNotFound
+	}
+	if m.conflicts(album) {
+		return -1, ErrAlreadyExists
+	}
+
+	return i, nil
+}
+
+// 現在のアルバムリストのスナップショットを返す
+// 要素は書き換えずに差し替えるため、スライスのコピーだけで一貫した状態を参照できる
+func (m *MemoryStore) snapshot() []*pb.Album {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.albums)
+}
+
+// IDが一致するアルバムの位置を返す（存在しない場合は-1）
+// 呼び出し元でロックを取得しておくこと
+func (m *MemoryStore) index(id string) int {
+	return slices.IndexFunc(m.albums, func(album *pb.Album) bool {
+		return album.Id == id
+	})
+}
+
+// 別のアルバムとIDが重複するか、タイトルとアーティストの組み合わせが重複するか判定する
+// 呼び出し元でロックを取得しておくこと
+func (m *MemoryStore) conflicts(album *pb.Album) bool {
+	return slices.ContainsFunc(m.albums, func(other *pb.Album) bool {
+		if other.Id == album.Id {
+			return false // 置き換え対象の自分自身（追加時のIDの重複はvalidateInsertで確認する）
+		}
+		return other.Title == album.Title && other.Artist == album.Artist
+	})
+}
+
+func clone(album *pb.Album) *pb.Album {
+	return proto.Clone(album).(*pb.Album)
+}
@@ -0,0 +1,437 @@
+package store
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// スキーマのマイグレーション
+// 適用済みのバージョンはPRAGMA user_versionに記録し、未適用のものだけを順に実行する
+// 既存のマイグレーションは書き換えず、変更は末尾に追加すること
+var migrations = []string{
+	// 1: albumsテーブルの作成（titleはUNIQUE制約によるインデックス、artistは検索用のインデックス）
+	`CREATE TABLE albums (
+		id     INTEGER PRIMARY KEY AUTOINCREMENT,
+		title  TEXT NOT NULL UNIQUE,
+		artist TEXT NOT NULL,
+		price  REAL NOT NULL
+	);
+	CREATE INDEX albums_artist_idx ON albums (artist);`,
+
+	// 2: サーバーが採番するIDを主キーにし、リリース年・ジャンル・曲・作成/更新日時を追加
+	// 同じタイトルでもアーティストが異なれば登録できるよう、UNIQUE制約を(title, artist)に変更する
+	// （この制約のインデックスがtitleでの検索にも使われる）
+	`CREATE TABLE albums_v2 (
+		id           TEXT PRIMARY KEY,
+		title        TEXT NOT NULL,
+		artist       TEXT NOT NULL,
+		price        REAL NOT NULL,
+		release_year INTEGER NOT NULL DEFAULT 0,
+		genre        TEXT NOT NULL DEFAULT '',
+		tracks       TEXT NOT NULL DEFAULT '[]',
+		created_at   TEXT NOT NULL,
+		updated_at   TEXT NOT NULL,
+		UNIQUE (title, artist)
+	);
+	INSERT INTO albums_v2 (id, title, artist, price, created_at, updated_at)
+		SELECT lower(hex(randomblob(16))), title, artist, price,
+			strftime('%Y-%m-%dT%H:%M:%fZ', 'now'), strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+		FROM albums ORDER BY id;
+	DROP TABLE albums;
+	ALTER TABLE albums_v2 RENAME TO albums;
+	CREATE INDEX albums_artist_idx ON albums (artist);`,
+
+	// 3: 価格をREALから通貨コード・整数部・ナノ単位の小数部に変更（価格がないアルバムはNULL）
+	// 既存の価格はfloat32由来で誤差を含むため、セント単位に丸めてから変換する
+	`ALTER TABLE albums ADD COLUMN price_currency TEXT;
+	ALTER TABLE albums ADD COLUMN price_units INTEGER;
+	ALTER TABLE albums ADD COLUMN price_nanos INTEGER;
+	UPDATE albums SET
+		price_currency = '` + DefaultCurrency + `',
+		price_units = CAST(price AS INTEGER),
+		price_nanos = CAST(ROUND((price - CAST(price AS INTEGER)) * 100) AS INTEGER) * 10000000;
+	ALTER TABLE albums DROP COLUMN price;`,
+}
+
+// SELECTする列（scanAlbumの引数の順番と合わせること）
+const albumColumns = `id, title, artist, price_currency, price_units, price_nanos, release_year, genre, tracks, created_at, updated_at`
+
+// SQLiteにアルバムを保存するAlbumStoreの実装
+// id・title・artistにインデックスがあるため、GetとtitleやartistによるListはインデックスを使った検索になる
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// SQLiteのデータベースファイルを開き、スキーマを最新に更新してSQLiteStoreを作成する
+func NewSQLiteStore(filePath string) (*SQLiteStore, error) {
+	// WALモードにして、書き込み中も読み取りをブロックしないようにする
+	dsn := fmt.Sprintf("file:%s?_journal_mode=WAL&_busy_timeout=5000&_synchronous=FULL", filePath)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*pb.Album, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+albumColumns+` FROM albums WHERE id = ?`, id)
+
+	album, err := scanAlbum(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return album, nil
+}
+
+// 件数の取得と範囲の取得の間に変更が入らないよう、1つのトランザクションで読み取る
+func (s *SQLiteStore) List(ctx context.Context, filter Filter, opts ListOptions) ([]*pb.Album, int, error) {
+	where, args := filterClause(filter)
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	var total int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM albums`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + albumColumns + ` FROM albums` + where + orderClause(opts)
+	if opts.Limit > 0 || opts.Offset > 0 {
+		limit := opts.Limit
+		if limit <= 0 {
+			limit = -1 // SQLiteではLIMIT -1で上限なし
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, opts.Offset)
+	}
+
+	albums, err := queryAlbums(ctx, tx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return albums, total, nil
+}
+
+func (s *SQLiteStore) Insert(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	album = clone(album)
+	prepareInsert(album, time.Now())
+
+	tracks, err := marshalTracks(album.Tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, units, nanos := priceColumns(album.Price)
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO albums (`+albumColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		album.Id, album.Title, album.Artist, currency, units, nanos, album.ReleaseYear, album.Genre, tracks,
+		formatTime(album.CreatedAt), formatTime(album.UpdatedAt),
+	)
+	if err != nil {
+		return nil, constraintError(err)
+	}
+
+	return album, nil
+}
+
+func (s *SQLiteStore) Update(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	current, err := s.Get(ctx, album.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	album = clone(album)
+	prepareUpdate(album, current, time.Now())
+
+	tracks, err := marshalTracks(album.Tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, units, nanos := priceColumns(album.Price)
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE albums SET title = ?, artist = ?, price_currency = ?, price_units = ?, price_nanos = ?,
+			release_year = ?, genre = ?, tracks = ?, updated_at = ? WHERE id = ?`,
+		album.Title, album.Artist, currency, units, nanos,
+		album.ReleaseYear, album.Genre, tracks, formatTime(album.UpdatedAt), album.Id,
+	)
+	if err != nil {
+		return nil, constraintError(err)
+	}
+	if err := affectedOne(res); err != nil {
+		return nil, err
+	}
+
+	return album, nil
+}
+
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM albums WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	return affectedOne(res)
+}
+
+// 読み取った結果に対してfnを呼ぶ
+// fn内でストアを操作しても接続を奪い合わないよう、先にすべての行を読み込んでおく
+func (s *SQLiteStore) Iterate(ctx context.Context, fn func(*pb.Album) bool) error {
+	albums, _, err := s.List(ctx, Filter{}, ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, album := range albums {
+		if !fn(album) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// データベースに書き込めるか確認するメソッド
+// 書き込みロックを取るトランザクションを開始してすぐにロールバックするため、データは変更しない
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, `ROLLBACK`)
+	return err
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// クエリを実行し、結果の行をアルバムのリストとして返す関数
+func queryAlbums(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]*pb.Album, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var albums []*pb.Album
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+
+	return albums, rows.Err()
+}
+
+// 未適用のマイグレーションを1つのトランザクションで適用するメソッド
+func (s *SQLiteStore) migrate(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, `PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+
+	for i, migration := range migrations[version:] {
+		if _, err := tx.ExecContext(ctx, migration); err != nil {
+			return fmt.Errorf("migration %d: %w", version+i+1, err)
+		}
+	}
+
+	// PRAGMAはプレースホルダを使えないため、値を直接埋め込む
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`PRAGMA user_version = %d`, len(migrations))); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// 更新・削除の対象が存在したか確認する関数
+func affectedOne(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// 絞り込み条件をWHERE句とその引数に変換する関数
+// NOCASEやlowerはASCII文字の大文字・小文字しか区別しない点がMemoryStoreと異なる
+func filterClause(filter Filter) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+
+	for _, f := range []struct{ column, value string }{{"title", filter.Title}, {"artist", filter.Artist}} {
+		if f.value == "" {
+			continue
+		}
+
+		switch {
+		case filter.MatchMode == MatchContains && filter.IgnoreCase:
+			conds = append(conds, `instr(lower(`+f.column+`), lower(?)) > 0`)
+		case filter.MatchMode == MatchContains:
+			conds = append(conds, `instr(`+f.column+`, ?) > 0`)
+		case filter.IgnoreCase:
+			conds = append(conds, f.column+` = ? COLLATE NOCASE`)
+		default:
+			conds = append(conds, f.column+` = ?`)
+		}
+		args = append(args, f.value)
+	}
+
+	if filter.MinPrice != nil {
+		conds = append(conds, `price_currency = ? AND (price_units > ? OR (price_units = ? AND price_nanos >= ?))`)
+		args = append(args, filter.MinPrice.CurrencyCode, filter.MinPrice.Units, filter.MinPrice.Units, filter.MinPrice.Nanos)
+	}
+	if filter.MaxPrice != nil {
+		conds = append(conds, `price_currency = ? AND (price_units < ? OR (price_units = ? AND price_nanos <= ?))`)
+		args = append(args, filter.MaxPrice.CurrencyCode, filter.MaxPrice.Units, filter.MaxPrice.Units, filter.MaxPrice.Nanos)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+
+	return ` WHERE (` + strings.Join(conds, `) AND (`) + `)`, args
+}
+
+// 並び順をORDER BY句に変換する関数
+// 並び順が同じ行はrowid（登録順）で並べる
+func orderClause(opts ListOptions) string {
+	var columns []string
+	switch opts.OrderBy {
+	case OrderByTitle:
+		columns = []string{"title"}
+	case OrderByArtist:
+		columns = []string{"artist"}
+	case OrderByPrice:
+		// NULL（価格なし）は昇順で先頭、降順で末尾になりMemoryStoreと同じ並びになる
+		columns = []string{"price_currency", "price_units", "price_nanos"}
+	default:
+		if opts.Descending {
+			return ` ORDER BY rowid DESC`
+		}
+		return ` ORDER BY rowid`
+	}
+
+	if opts.Descending {
+		for i := range columns {
+			columns[i] += ` DESC`
+		}
+	}
+
+	return ` ORDER BY ` + strings.Join(columns, `, `) + `, rowid`
+}
+
+// 一意制約の違反をErrAlreadyExistsに変換する関数
+func constraintError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey) {
+		return ErrAlreadyExists
+	}
+
+	return err
+}
+
+// albumColumnsの順にSELECTした1行をアルバムに変換する関数
+func scanAlbum(row interface{ Scan(dest ...any) error }) (*pb.Album, error) {
+	var (
+		album                = &pb.Album{}
+		currency             sql.NullString
+		units, nanos         sql.NullInt64
+		tracks               []byte
+		createdAt, updatedAt string
+	)
+	err := row.Scan(
+		&album.Id, &album.Title, &album.Artist, &currency, &units, &nanos, &album.ReleaseYear, &album.Genre,
+		&tracks, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if currency.Valid {
+		album.Price = &moneypb.Money{CurrencyCode: currency.String, Units: units.Int64, Nanos: int32(nanos.Int64)}
+	}
+
+	if album.Tracks, err = unmarshalTracks(tracks); err != nil {
+		return nil, err
+	}
+	if album.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, err
+	}
+	if album.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, err
+	}
+
+	return album, nil
+}
+
+// 価格を通貨コード・整数部・小数部の列の値に分ける関数（価格がない場合はすべてNULL）
+func priceColumns(price *moneypb.Money) (currency, units, nanos any) {
+	if price == nil {
+		return nil, nil, nil
+	}
+
+	return price.CurrencyCode, price.Units, price.Nanos
+}
+
+// 日時はUTCのRFC3339形式の文字列で保存する
+func formatTime(ts *timestamppb.Timestamp) string {
+	return ts.AsTime().UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(value string) (*timestamppb.Timestamp, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return timestamppb.New(t), nil
+}
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func usd(units int64, nanos int32) *moneypb.Money {
+	return &moneypb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+// テストで登録するアルバム
+// ストアの間で結果を比較できるよう、サーバーが設定する項目も指定しておく
+func testAlbums() []*pb.Album {
+	created := timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	return []*pb.Album{
+		{Id: "a1", Title: "Blue Train", Artist: "John Coltrane", Price: usd(56, 990000000), ReleaseYear: 1957, Genre: "Jazz", CreatedAt: created, UpdatedAt: created},
+		{Id: "a2", Title: "Jeru", Artist: "Gerry Mulligan", Price: usd(17, 990000000), ReleaseYear: 1962, Genre: "Jazz", CreatedAt: created, UpdatedAt: created},
+		{Id: "a3", Title: "Giant Steps", Artist: "John Coltrane", Price: usd(39, 990000000), ReleaseYear: 1960, Genre: "Jazz", CreatedAt: created, UpdatedAt: created,
+			Tracks: []*pb.Track{{Number: 1, Title: "Giant Steps"}, {Number: 2, Title: "Cousin Mary"}}},
+		{Id: "a4", Title: "Kind of Blue", Artist: "Miles Davis", Price: usd(29, 990000000), ReleaseYear: 1959, Genre: "Modal Jazz", CreatedAt: created, UpdatedAt: created},
+		{Id: "a5", Title: "Somethin' Else", Artist: "Cannonball Adderley", Price: &moneypb.Money{CurrencyCode: "JPY", Units: 2500}, CreatedAt: created, UpdatedAt: created},
+		{Id: "a6", Title: "blue train", Artist: "Tribute Band", CreatedAt: created, UpdatedAt: created}, // 価格なし
+		{Id: "a7", Title: "Ballads", Artist: "John Coltrane", Price: usd(17, 990000000), CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+// versionまでのマイグレーションを適用したデータベースファイルを作成する関数
+// 古いスキーマのデータベースを再現するため、SQLiteStoreを使わずに直接適用する
+func newDBAtVersion(t *testing.T, version int, setup ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "album.db")
+	db, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for i, migration := range migrations[:version] {
+		if _, err := db.Exec(migration); err != nil {
+			t.Fatalf("migration %d: %v", i+1, err)
+		}
+	}
+	if _, err := db.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, version)); err != nil {
+		t.Fatal(err)
+	}
+	for _, stmt := range setup {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("setup %q: %v", stmt, err)
+		}
+	}
+
+	return path
+}
+
+func userVersion(t *testing.T, s *SQLiteStore) int {
+	t.Helper()
+
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestSQLiteMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int      // マイグレーション前のスキーマのバージョン
+		setup   []string // マイグレーション前に登録しておくデータ
+		want    []*pb.Album
+	}{
+		{name: "empty database", version: 0},
+		{
+			name:    "from version 1",
+			version: 1,
+			setup:   []string{`INSERT INTO albums (title, artist, price) VALUES ('Blue Train', 'John Coltrane', 56.99)`},
+			want:    []*pb.Album{{Title: "Blue Train", Artist: "John Coltrane", Price: usd(56, 990000000)}},
+		},
+		{
+			name:    "from version 2",
+			version: 2,
+			setup: []string{`INSERT INTO albums (id, title, artist, price, release_year, created_at, updated_at) VALUES
+				('a1', 'Blue Train', 'John Coltrane', 56.9900016784668, 1957, '2024-01-02T03:04:05Z', '2024-01-02T03:04:05Z'),
+				('a2', 'Jeru', 'Gerry Mulligan', 17.989999771118164, 1962, '2024-01-02T03:04:05Z', '2024-01-02T03:04:05Z')`},
+			// float32由来の誤差はセント単位に丸められる
+			want: []*pb.Album{
+				{Title: "Blue Train", Artist: "John Coltrane", Price: usd(56, 990000000), ReleaseYear: 1957},
+				{Title: "Jeru", Artist: "Gerry Mulligan", Price: usd(17, 990000000), ReleaseYear: 1962},
+			},
+		},
+		{
+			name:    "latest",
+			version: len(migrations),
+			setup: []string{`INSERT INTO albums (id, title, artist, price_currency, price_units, price_nanos, release_year, created_at, updated_at) VALUES
+				('a1', 'Blue Train', 'John Coltrane', 'JPY', 5000, 0, 1957, '2024-01-02T03:04:05Z', '2024-01-02T03:04:05Z'),
+				('a2', 'Jeru', 'Gerry Mulligan', NULL, NULL, NULL, 1962, '2024-01-02T03:04:05Z', '2024-01-02T03:04:05Z')`},
+			want: []*pb.Album{
+				{Title: "Blue Train", Artist: "John Coltrane", Price: &moneypb.Money{CurrencyCode: "JPY", Units: 5000}, ReleaseYear: 1957},
+				{Title: "Jeru", Artist: "Gerry Mulligan", ReleaseYear: 1962},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			path := newDBAtVersion(t, tt.version, tt.setup...)
+
+			s, err := NewSQLiteStore(path)
+			if err != nil {
+				t.Fatalf("NewSQLiteStore() error = %v", err)
+			}
+			defer s.Close()
+
+			if got := userVersion(t, s); got != len(migrations) {
+				t.Errorf("user_version = %d, want %d", got, len(migrations))
+			}
+			albums, _, err := s.List(ctx, Filter{}, ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 移行したアルバムにもIDと作成日時が設定され、IDで取得できる
+			for _, album := range albums {
+				if album.Id == "" || album.CreatedAt == nil || album.UpdatedAt == nil {
+					t.Errorf("migrated album has no id or timestamps: %v", album)
+				}
+				if _, err := s.Get(ctx, album.Id); err != nil {
+					t.Errorf("Get(%q) error = %v", album.Id, err)
+				}
+			}
+			assertAlbums(t, withoutServerFields(albums), tt.want)
+
+			// 最新のスキーマで読み書きできる
+			album := testAlbums()[2]
+			if _, err := s.Insert(ctx, album); err != nil {
+				t.Fatalf("Insert() error = %v", err)
+			}
+			got, err := s.Get(ctx, album.Id)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			assertAlbums(t, []*pb.Album{got}, []*pb.Album{album})
+		})
+	}
+}
+
+// 対応しているより新しいスキーマのデータベースは開かない
+func TestSQLiteMigrateNewerVersion(t *testing.T) {
+	path := newDBAtVersion(t, len(migrations), fmt.Sprintf(`PRAGMA user_version = %d`, len(migrations)+1))
+
+	if s, err := NewSQLiteStore(path); err == nil {
+		s.Close()
+		t.Fatal("NewSQLiteStore() error = nil, want error for a newer schema")
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	ctx := context.Background()
+	albums := testAlbums()
+	data, err := marshalAlbums(albums)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(t.TempDir(), "album.json")
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "album.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	n, err := ImportJSON(ctx, s, jsonPath)
+	if err != nil || n != len(albums) {
+		t.Fatalf("ImportJSON() = %d, %v, want %d, nil", n, err, len(albums))
+	}
+	// IDや作成日時を含めて、そのまま取り込まれる
+	imported, _, err := s.List(ctx, Filter{}, ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertAlbums(t, imported, albums)
+
+	// 取り込み済みのアルバムはスキップする
+	if n, err := ImportJSON(ctx, s, jsonPath); err != nil || n != 0 {
+		t.Errorf("second ImportJSON() = %d, %v, want 0, nil", n, err)
+	}
+}
+
+// 同じデータと条件に対して、MemoryStoreとSQLiteStoreが同じ結果を返すことを確認する
+func TestSQLiteMatchesMemory(t *testing.T) {
+	ctx := context.Background()
+	memory := NewMemoryStore()
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "album.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sqlite.Close()
+
+	for _, s := range []AlbumStore{memory, sqlite} {
+		for _, album := range testAlbums() {
+			if _, err := s.Insert(ctx, album); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	filters := []Filter{
+		{},
+		{Artist: "John Coltrane"},
+		{Title: "Jeru"},
+		{Title: "Blue Train", Artist: "Miles Davis"},
+		{Title: "blue", MatchMode: MatchContains},
+		{Title: "BLUE", MatchMode: MatchContains, IgnoreCase: true},
+		{Title: "blue train", IgnoreCase: true},
+		{Artist: "coltrane", MatchMode: MatchContains, IgnoreCase: true},
+		{Title: "%", MatchMode: MatchContains}, // LIKEのワイルドカードは文字として扱う
+		{MinPrice: usd(20, 0)},
+		{MaxPrice: usd(29, 990000000)},
+		{MinPrice: usd(17, 990000000), MaxPrice: usd(39, 990000000)},
+		{MinPrice: &moneypb.Money{CurrencyCode: "JPY", Units: 1000}},
+	}
+	options := []ListOptions{
+		{},
+		{OrderBy: OrderByTitle},
+		{OrderBy: OrderByArtist},
+		{OrderBy: OrderByArtist, Descending: true},
+		{OrderBy: OrderByPrice},
+		{OrderBy: OrderByPrice, Descending: true},
+		{Descending: true},
+		{Limit: 2},
+		{OrderBy: OrderByTitle, Offset: 1, Limit: 3},
+		{OrderBy: OrderByPrice, Offset: 5},
+	}
+	for i, filter := range filters {
+		for _, opts := range options {
+			// Filterの価格はポインタのため、名前には番号を使う
+			t.Run(fmt.Sprintf("filter %d/%+v", i, opts), func(t *testing.T) {
+				want, wantTotal, err := memory.List(ctx, filter, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, gotTotal, err := sqlite.List(ctx, filter, opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if gotTotal != wantTotal {
+					t.Errorf("total = %d, want %d", gotTotal, wantTotal)
+				}
+				assertAlbums(t, got, want)
+			})
+		}
+	}
+}
+
+// サーバーが設定するIDと作成・更新日時を取り除いたコピーを返す関数
+func withoutServerFields(albums []*pb.Album) []*pb.Album {
+	stripped := make([]*pb.Album, len(albums))
+	for i, album := range albums {
+		stripped[i] = clone(album)
+		stripped[i].Id = ""
+		stripped[i].CreatedAt = nil
+		stripped[i].UpdatedAt = nil
+	}
+	return stripped
+}
+
+func assertAlbums(t *testing.T, got, want []*pb.Album) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d albums, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("album[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
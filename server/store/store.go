@@ -0,0 +1,197 @@
+package store
+
+import (
+	"awsomeProject/money"
+	"awsomeProject/pb"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// 通貨の指定がない古い形式の価格を読み込むときに使う通貨コード
+const DefaultCurrency = "USD"
+
+var (
+	ErrNotFound      = errors.New("album not found")      // 指定したアルバムが存在しない
+	ErrAlreadyExists = errors.New("album already exists") // 同じIDや、同じタイトルとアーティストのアルバムが既に存在する
+)
+
+// 文字列の条件の一致方法
+type MatchMode int
+
+const (
+	MatchExact    MatchMode = iota // 完全一致
+	MatchContains                  // 部分一致
+)
+
+// アルバムを絞り込むための条件
+// 空の項目は条件として扱わない
+type Filter struct {
+	Title      string
+	Artist     string
+	MatchMode  MatchMode // TitleとArtistの一致方法
+	IgnoreCase bool      // TitleとArtistの大文字・小文字を区別しない
+	MinPrice   *moneypb.Money
+	MaxPrice   *moneypb.Money // 価格の範囲（価格がないアルバムや通貨が異なるアルバムは一致しない）
+}
+
+// アルバムが条件に一致するか判定するメソッド
+func (f Filter) Match(album *pb.Album) bool {
+	if f.Title != "" && !f.matchString(album.Title, f.Title) {
+		return false
+	}
+	if f.Artist != "" && !f.matchString(album.Artist, f.Artist) {
+		return false
+	}
+	if f.MinPrice != nil && !comparePrice(album.Price, f.MinPrice, func(c int) bool { return c >= 0 }) {
+		return false
+	}
+	if f.MaxPrice != nil && !comparePrice(album.Price, f.MaxPrice, func(c int) bool { return c <= 0 }) {
+		return false
+	}
+
+	return true
+}
+
+func (f Filter) matchString(value, pattern string) bool {
+	if f.IgnoreCase {
+		value, pattern = strings.ToLower(value), strings.ToLower(pattern)
+	}
+	if f.MatchMode == MatchContains {
+		return strings.Contains(value, pattern)
+	}
+
+	return value == pattern
+}
+
+// 価格を比較し、結果がokを満たすか判定する関数
+func comparePrice(price, bound *moneypb.Money, ok func(int) bool) bool {
+	if price == nil {
+		return false
+	}
+
+	c, err := money.Compare(price, bound)
+	return err == nil && ok(c)
+}
+
+// 並び替えに使うフィールド
+type OrderBy string
+
+const (
+	OrderByDefault OrderBy = ""       // 登録順
+	OrderByTitle   OrderBy = "title"  // タイトル順
+	OrderByArtist  OrderBy = "artist" // アーティスト順
+	OrderByPrice   OrderBy = "price"  // 通貨コード順、同じ通貨の中では金額順（価格がないアルバムが先頭）
+)
+
+// Listの並び順と取得する範囲
+// 並び順が同じアルバムは登録順に並ぶ
+type ListOptions struct {
+	OrderBy    OrderBy
+	Descending bool
+	Offset     int // 先頭から読み飛ばす件数
+	Limit      int // 取得する最大件数（0の場合はすべて）
+}
+
+// ListOptionsに従ってアルバムを並び替え、取得する範囲を切り出すメソッド
+func (o ListOptions) apply(albums []*pb.Album) []*pb.Album {
+	if o.OrderBy != OrderByDefault {
+		slices.SortStableFunc(albums, func(a, b *pb.Album) int {
+			c := compareAlbums(a, b, o.OrderBy)
+			if o.Descending {
+				return -c
+			}
+			return c
+		})
+	} else if o.Descending {
+		slices.Reverse(albums)
+	}
+
+	if o.Offset >= len(albums) {
+		return nil
+	}
+	albums = albums[o.Offset:]
+	if o.Limit > 0 && o.Limit < len(albums) {
+		albums = albums[:o.Limit]
+	}
+
+	return albums
+}
+
+func compareAlbums(a, b *pb.Album, orderBy OrderBy) int {
+	switch orderBy {
+	case OrderByTitle:
+		return strings.Compare(a.Title, b.Title)
+	case OrderByArtist:
+		return strings.Compare(a.Artist, b.Artist)
+	case OrderByPrice:
+		if a.Price == nil || b.Price == nil {
+			return cmp.Compare(boolToInt(a.Price != nil), boolToInt(b.Price != nil))
+		}
+		if c := strings.Compare(a.Price.CurrencyCode, b.Price.CurrencyCode); c != 0 {
+			return c
+		}
+		c, _ := money.Compare(a.Price, b.Price) // 通貨が同じためエラーにならない
+		return c
+	default:
+		return 0
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// アルバムデータの保存先を抽象化したインターフェース
+// AlbumServerはこのインターフェースを通してのみアルバムを操作する
+//
+// アルバムはサーバーが採番するIDで識別する
+// IDと作成・更新日時はストアが設定し、InsertとUpdateは保存後のアルバムを返す
+type AlbumStore interface {
+	Get(ctx context.Context, id string) (*pb.Album, error)                               // IDでアルバムを1件取得
+	List(ctx context.Context, filter Filter, opts ListOptions) ([]*pb.Album, int, error) // 条件に一致するアルバムと、条件に一致する総数を取得
+	Insert(ctx context.Context, album *pb.Album) (*pb.Album, error)                      // アルバムを新規追加
+	Update(ctx context.Context, album *pb.Album) (*pb.Album, error)                      // 既存のアルバムを更新
+	Delete(ctx context.Context, id string) error                                         // アルバムを削除
+	Iterate(ctx context.Context, fn func(*pb.Album) bool) error                          // すべてのアルバムを順に処理（fnがfalseを返すと中断）
+	Ping(ctx context.Context) error                                                      // 保存先が読み書きできる状態か確認
+	Close() error                                                                        // 保持しているリソースを解放
+}
+
+// 新しいアルバムIDを採番する関数
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b) // crypto/randのReadはエラーを返さない
+	return hex.EncodeToString(b)
+}
+
+// 新規追加するアルバムにIDと作成・更新日時を設定する関数
+// 既に設定されている値（JSONファイルからの移行や取り込み時）はそのまま使う
+func prepareInsert(album *pb.Album, now time.Time) {
+	if album.Id == "" {
+		album.Id = NewID()
+	}
+	if album.CreatedAt == nil {
+		album.CreatedAt = timestamppb.New(now)
+	}
+	if album.UpdatedAt == nil {
+		album.UpdatedAt = album.CreatedAt
+	}
+}
+
+// 更新するアルバムに、変更できない作成日時と新しい更新日時を設定する関数
+func prepareUpdate(album, current *pb.Album, now time.Time) {
+	album.CreatedAt = current.CreatedAt
+	album.UpdatedAt = timestamppb.New(now)
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"awsomeProject/pb"
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ストアの操作ごとにスパンを作成するAlbumStoreのデコレーター
+// RPCのスパンを持つコンテキストで呼ばれた場合は、その子スパンになる
+type TracedStore struct {
+	AlbumStore
+
+	tracer trace.Tracer
+}
+
+// storeの操作をtracerで記録するTracedStoreを作成する
+func NewTracedStore(store AlbumStore, tracer trace.Tracer) *TracedStore {
+	return &TracedStore{AlbumStore: store, tracer: tracer}
+}
+
+func (s *TracedStore) Get(ctx context.Context, id string) (*pb.Album, error) {
+	ctx, span := s.start(ctx, "store.Get", attribute.String("album.id", id))
+	album, err := s.AlbumStore.Get(ctx, id)
+	endSpan(span, err)
+
+	return album, err
+}
+
+func (s *TracedStore) List(ctx context.Context, filter Filter, opts ListOptions) ([]*pb.Album, int, error) {
+	ctx, span := s.start(ctx, "store.List",
+		attribute.Int("list.offset", opts.Offset),
+		attribute.Int("list.limit", opts.Limit),
+	)
+	albums, total, err := s.AlbumStore.List(ctx, filter, opts)
+	span.SetAttributes(attribute.Int("list.total", total), attribute.Int("list.returned", len(albums)))
+	endSpan(span, err)
+
+	return albums, total, err
+}
+
+func (s *TracedStore) Insert(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	ctx, span := s.start(ctx, "store.Insert", attribute.String("album.title", album.Title))
+	inserted, err := s.AlbumStore.Insert(ctx, album)
+	if err == nil {
+		span.SetAttributes(attribute.String("album.id", inserted.Id))
+	}
+	endSpan(span, err)
+
+	return inserted, err
+}
+
+func (s *TracedStore) Update(ctx context.Context, album *pb.Album) (*pb.Album, error) {
+	ctx, span := s.start(ctx, "store.Update", attribute.String("album.id", album.Id))
+	updated, err := s.AlbumStore.Update(ctx, album)
+	endSpan(span, err)
+
+	return updated, err
+}
+
+func (s *TracedStore) Delete(ctx context.Context, id string) error {
+	ctx, span := s.start(ctx, "store.Delete", attribute.String("album.id", id))
+	err := s.AlbumStore.Delete(ctx, id)
+	endSpan(span, err)
+
+	return err
+}
+
+func (s *TracedStore) Iterate(ctx context.Context, fn func(*pb.Album) bool) error {
+	ctx, span := s.start(ctx, "store.Iterate")
+	err := s.AlbumStore.Iterate(ctx, fn)
+	endSpan(span, err)
+
+	return err
+}
+
+func (s *TracedStore) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal), trace.WithAttributes(attrs...))
+}
+
+// 操作の結果をスパンに記録して終了する関数
+// 見つからない・既に存在するといった想定内のエラーはスパンのエラーにしない
+func endSpan(span trace.Span, err error) {
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrAlreadyExists):
+		span.SetAttributes(attribute.String("store.result", err.Error()))
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"awsomeProject/pb"
+	"awsomeProject/server/interceptor"
+	"awsomeProject/server/store"
+	"awsomeProject/tracing"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// RPCごとのスパン、送受信したメッセージのイベント、ストアの操作の子スパンが記録されることを確認する
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := tracing.NewTracerProvider(exporter, "album-server-test", 1)
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	memory := store.NewMemoryStore(seedAlbums()...)
+	seeded, _, err := memory.List(context.Background(), store.Filter{}, store.ListOptions{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := startAlbumService(t, store.NewTracedStore(memory, tracing.Tracer()),
+		grpc.ChainUnaryInterceptor(interceptor.TracingUnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(interceptor.TracingStreamServerInterceptor()),
+	)
+
+	// 索引の作成時のスパンは対象外にする
+	tp.ForceFlush(context.Background())
+	exporter.Reset()
+
+	// 終了したスパンをすべて書き出してから返す関数
+	spans := func() tracetest.SpanStubs {
+		t.Helper()
+		if err := tp.ForceFlush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return exporter.GetSpans()
+	}
+	// 記録されたスパンを名前で探す関数
+	find := func(name string) []tracetest.SpanStub {
+		var found []tracetest.SpanStub
+		for _, span := range spans() {
+			if span.Name == name {
+				found = append(found, span)
+			}
+		}
+		return found
+	}
+
+	t.Run("Unary", func(t *testing.T) {
+		exporter.Reset()
+		if _, err := client.GetAlbum(context.Background(), &pb.GetAlbumRequest{Id: seeded[0].Id}); err != nil {
+			t.Fatal(err)
+		}
+
+		rpc := onlySpan(t, find("album.AlbumService/GetAlbum"))
+		if rpc.SpanKind != trace.SpanKindServer {
+			t.Errorf("span kind = %v, want server", rpc.SpanKind)
+		}
+		assertMessageEvents(t, rpc, map[string]int{"RECEIVED": 1, "SENT": 1})
+		assertChild(t, rpc, onlySpan(t, find("store.Get")))
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		exporter.Reset()
+		stream, err := client.UploadAndNotify(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, title := range []string{"Giant Steps", "Mingus Ah Um"} {
+			if err := stream.Send(&pb.UploadAndNotifyRequest{Album: &pb.Album{Title: title, Artist: "Test Artist"}}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := stream.Recv(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		stream.CloseSend()
+		if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+			t.Fatalf("stream did not end cleanly: %v", err)
+		}
+
+		rpc := onlySpan(t, find("album.AlbumService/UploadAndNotify"))
+		assertMessageEvents(t, rpc, map[string]int{"RECEIVED": 2, "SENT": 2})
+		inserts := find("store.Insert")
+		if len(inserts) != 2 {
+			t.Fatalf("store.Insert spans = %d, want 2", len(inserts))
+		}
+		for _, insert := range inserts {
+			assertChild(t, rpc, insert)
+		}
+	})
+}
+
+func onlySpan(t *testing.T, spans []tracetest.SpanStub) tracetest.SpanStub {
+	t.Helper()
+
+	if len(spans) != 1 {
+		t.Fatalf("found %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+// スパンのmessageイベントを、message.typeごとに数えて確認する関数
+func assertMessageEvents(t *testing.T, span tracetest.SpanStub, want map[string]int) {
+	t.Helper()
+
+	got := map[string]int{}
+	for _, event := range span.Events {
+		if event.Name != "message" {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == attribute.Key("message.type") {
+				got[attr.Value.AsString()]++
+			}
+		}
+	}
+	for typ, n := range want {
+		if got[typ] != n {
+			t.Errorf("%s: %s message events = %d, want %d", span.Name, typ, got[typ], n)
+		}
+	}
+}
+
+func assertChild(t *testing.T, parent, child tracetest.SpanStub) {
+	t.Helper()
+
+	if child.Parent.SpanID() != parent.SpanContext.SpanID() || child.SpanContext.TraceID() != parent.SpanContext.TraceID() {
+		t.Errorf("%s is not a child of %s", child.Name, parent.Name)
+	}
+}
@@ -0,0 +1,118 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// 証明書と秘密鍵のファイルを監視し、更新されたら読み込み直す構造体
+//
+// ハンドシェイクのたびにファイルの更新日時を確認するため、サーバーを再起動せずに証明書を差し替えられる
+// 読み込みに失敗した場合は、直前に読み込めた証明書を使い続ける
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	certMod  time.Time // 読み込んだときの証明書ファイルの更新日時
+	keyMod   time.Time // 読み込んだときの秘密鍵ファイルの更新日時
+	lastStat time.Time // 最後に更新日時を確認した時刻
+}
+
+// 更新日時を確認する最短の間隔（ハンドシェイクが集中してもファイルシステムに負荷をかけない）
+const statInterval = 1 * time.Second
+
+// 証明書と秘密鍵を読み込んでReloaderを作成する関数
+// 起動時の設定の誤りに気付けるよう、最初の読み込みに失敗した場合はエラーを返す
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// tls.Config.GetCertificateに設定するメソッド
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.certificate(), nil
+}
+
+// tls.Config.GetClientCertificateに設定するメソッド
+func (r *Reloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.certificate(), nil
+}
+
+// ファイルが更新されていれば読み込み直してから、現在の証明書を返すメソッド
+func (r *Reloader) certificate() *tls.Certificate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastStat) < statInterval {
+		return r.cert
+	}
+	r.lastStat = now
+
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		slog.Warn("failed to check TLS certificate", "cert_file", r.certFile, "error", err)
+		return r.cert
+	}
+	if certMod.Equal(r.certMod) && keyMod.Equal(r.keyMod) {
+		return r.cert
+	}
+
+	// 証明書と秘密鍵の片方だけが書き換えられた途中の状態では読み込みに失敗するため、次の確認で再度試す
+	if err := r.reloadLocked(); err != nil {
+		slog.Warn("failed to reload TLS certificate", "cert_file", r.certFile, "error", err)
+		return r.cert
+	}
+	slog.Info("reloaded TLS certificate", "cert_file", r.certFile)
+
+	return r.cert
+}
+
+func (r *Reloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.reloadLocked()
+}
+
+// 証明書と秘密鍵を読み込むメソッド（呼び出し側がmuのロックを取っていること）
+func (r *Reloader) reloadLocked() error {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+
+	r.cert = &cert
+	r.certMod = certMod
+	r.keyMod = keyMod
+	r.lastStat = time.Now()
+
+	return nil
+}
+
+func (r *Reloader) modTimes() (certMod, keyMod time.Time, err error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
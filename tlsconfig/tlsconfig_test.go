@@ -0,0 +1,298 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// テスト用に作成したCA
+type testCA struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	pemFile string
+}
+
+func newTestCA(t *testing.T, dir, name string) *testCA {
+	t.Helper()
+
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber:          newSerial(t),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pemFile := filepath.Join(dir, name+".pem")
+	writePEM(t, pemFile, "CERTIFICATE", der)
+
+	return &testCA{cert: cert, key: key, pemFile: pemFile}
+}
+
+// CAが署名した証明書と秘密鍵をdirに書き出し、それぞれのファイルのパスを返すメソッド
+// serverがtrueの場合はlocalhostのサーバー証明書、falseの場合はクライアント証明書を作成する
+func (ca *testCA) issue(t *testing.T, dir, name string, server bool) (certFile, keyFile string) {
+	t.Helper()
+
+	key := newKey(t)
+	tmpl := &x509.Certificate{
+		SerialNumber: newSerial(t),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		tmpl.DNSNames = []string{"localhost"}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile = filepath.Join(dir, name+".crt")
+	keyFile = filepath.Join(dir, name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+
+	return certFile, keyFile
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newSerial(t *testing.T) *big.Int {
+	t.Helper()
+
+	n, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ループバックの接続でTLSのハンドシェイクを行い、サーバー側とクライアント側のエラーを返す関数
+func handshake(t *testing.T, serverCfg, clientCfg *tls.Config) (serverErr, clientErr error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		done <- tls.Server(conn, serverCfg).Handshake()
+	}()
+
+	conn, err := net.Dial("tcp", lis.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	client := tls.Client(conn, clientCfg)
+	clientErr = client.Handshake()
+	serverErr = <-done
+	if clientErr == nil {
+		// TLS 1.3ではクライアント証明書を拒否されたことがハンドシェイクの後に届くため、読み込んで確認する
+		client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if _, err := client.Read(make([]byte, 1)); err != nil && !isTimeout(err) && !errors.Is(err, io.EOF) {
+			clientErr = err
+		}
+	}
+
+	return serverErr, clientErr
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+func TestTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	serverCert, serverKey := ca.issue(t, dir, "server", true)
+
+	serverCfg, err := Server(serverCert, serverKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCfg, err := Client(ca.pemFile, "", "", "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serverErr, clientErr := handshake(t, serverCfg, clientCfg)
+	if serverErr != nil || clientErr != nil {
+		t.Fatalf("handshake failed: server: %v, client: %v", serverErr, clientErr)
+	}
+
+	// 別のCAを信頼するクライアントはサーバー証明書を受け入れない
+	otherCA := newTestCA(t, dir, "other-ca")
+	untrusting, err := Client(otherCA.pemFile, "", "", "localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, clientErr := handshake(t, serverCfg, untrusting); clientErr == nil {
+		t.Error("client accepted a server certificate signed by an unknown CA")
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	otherCA := newTestCA(t, dir, "other-ca")
+	serverCert, serverKey := ca.issue(t, dir, "server", true)
+	clientCert, clientKey := ca.issue(t, dir, "client", false)
+	otherCert, otherKey := otherCA.issue(t, dir, "other-client", false)
+
+	serverCfg, err := Server(serverCert, serverKey, ca.pemFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		certFile string
+		keyFile  string
+		wantErr  bool
+	}{
+		{name: "client certificate signed by the CA", certFile: clientCert, keyFile: clientKey},
+		{name: "no client certificate", wantErr: true},
+		{name: "client certificate signed by another CA", certFile: otherCert, keyFile: otherKey, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientCfg, err := Client(ca.pemFile, tt.certFile, tt.keyFile, "localhost")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			serverErr, clientErr := handshake(t, serverCfg, clientCfg)
+			if tt.wantErr {
+				if serverErr == nil {
+					t.Error("server accepted the client")
+				}
+				if clientErr == nil {
+					t.Error("client was not notified of the rejection")
+				}
+				return
+			}
+			if serverErr != nil || clientErr != nil {
+				t.Errorf("handshake failed: server: %v, client: %v", serverErr, clientErr)
+			}
+		})
+	}
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir, "ca")
+	certFile, keyFile := ca.issue(t, dir, "server", true)
+
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := r.certificate().Leaf
+
+	// 同じパスに新しい証明書と秘密鍵を書き出す（更新日時が確実に変わるよう、未来の時刻にする）
+	renewedDir := t.TempDir()
+	renewedCert, renewedKey := ca.issue(t, renewedDir, "server", true)
+	for src, dst := range map[string]string{renewedCert: certFile, renewedKey: keyFile} {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dst, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		future := time.Now().Add(time.Minute)
+		if err := os.Chtimes(dst, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// statIntervalが経つまではファイルを確認しない
+	if got := r.certificate().Leaf; !got.Equal(first) {
+		t.Error("certificate was reloaded before statInterval elapsed")
+	}
+
+	time.Sleep(statInterval + 100*time.Millisecond)
+	got := r.certificate().Leaf
+	if got.Equal(first) {
+		t.Fatal("certificate was not reloaded after statInterval")
+	}
+	if got.SerialNumber.Cmp(first.SerialNumber) == 0 {
+		t.Error("reloaded certificate has the old serial number")
+	}
+
+	// 読み込みに失敗した場合は直前の証明書を使い続ける
+	if err := os.WriteFile(certFile, []byte("broken"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(2 * time.Minute)
+	if err := os.Chtimes(certFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(statInterval + 100*time.Millisecond)
+	if cert := r.certificate(); cert == nil || !cert.Leaf.Equal(got) {
+		t.Error("reloader did not keep the last good certificate")
+	}
+}
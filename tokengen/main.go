@@ -0,0 +1,55 @@
+package main
+
+import (
+	"awsomeProject/auth"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	secretFile = flag.String("secret-file", "", "file containing the HMAC secret shared with the server")
+	subject    = flag.String("sub", "", "subject of the token")
+	roles      = flag.String("roles", "", "comma-separated roles of the subject")
+	ttl        = flag.Duration("ttl", 1*time.Hour, "lifetime of the token")
+	issuer     = flag.String("iss", "", "issuer of the token")
+	audience   = flag.String("aud", "", "comma-separated audiences of the token")
+)
+
+// サーバーの-jwt-secret-fileと同じ鍵でJWTを発行し、標準出力に書き出すコマンド
+func main() {
+	flag.Parse()
+
+	if *secretFile == "" || *subject == "" {
+		log.Fatal("-secret-file and -sub are required")
+	}
+
+	secret, err := auth.LoadSecret(*secretFile)
+	if err != nil {
+		log.Fatalf("failed to load secret: %v", err)
+	}
+
+	now := time.Now()
+	claims := auth.Claims{
+		Subject:   *subject,
+		Issuer:    *issuer,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: now.Add(*ttl).Unix(),
+	}
+	if *roles != "" {
+		claims.Roles = strings.Split(*roles, ",")
+	}
+	if *audience != "" {
+		claims.Audience = strings.Split(*audience, ",")
+	}
+
+	token, err := auth.SignJWT(secret, claims)
+	if err != nil {
+		log.Fatalf("failed to sign token: %v", err)
+	}
+
+	fmt.Println(token)
+}
@@ -0,0 +1,187 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// gRPCのメタデータをpropagation.TextMapCarrierとして扱う型
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// クライアントから受け取ったメタデータのトレースの情報をコンテキストに取り込む関数
+func Extract(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return otel.GetTextMapPropagator().Extract(ctx, MetadataCarrier(md.Copy()))
+}
+
+// コンテキストのトレースの情報を送信するメタデータに載せる関数
+func Inject(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, MetadataCarrier(md))
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// RPCのスパンに付ける属性を返す関数
+func RPCAttributes(fullMethod string) []attribute.KeyValue {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return []attribute.KeyValue{
+		semconv.RPCSystemGRPC,
+		semconv.RPCService(service),
+		semconv.RPCMethod(method),
+	}
+}
+
+// RPCの結果をスパンに記録する関数
+func EndRPC(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if err != nil {
+		span.SetStatus(codes.Error, status.Convert(err).Message())
+	}
+	span.End()
+}
+
+// 送受信したメッセージをスパンのイベントとして記録する関数
+// idはストリーム内で何番目のメッセージか（1から数える）
+func MessageEvent(span trace.Span, messageType string, id int64, m interface{}) {
+	attrs := []attribute.KeyValue{
+		attribute.String("message.type", messageType),
+		attribute.Int64("message.id", id),
+	}
+	if msg, ok := m.(proto.Message); ok {
+		attrs = append(attrs, attribute.Int("message.uncompressed_size", proto.Size(msg)))
+	}
+
+	span.AddEvent("message", trace.WithAttributes(attrs...))
+}
+
+// Unary RPCのスパンを作成し、トレースの情報をサーバーに送るクライアントのインターセプター
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := Tracer().Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(RPCAttributes(method)...),
+		)
+
+		MessageEvent(span, "SENT", 1, req)
+		err := invoker(Inject(ctx), method, req, reply, cc, opts...)
+		if err == nil {
+			MessageEvent(span, "RECEIVED", 1, reply)
+		}
+		EndRPC(span, err)
+
+		return err
+	}
+}
+
+// ストリーム形式のRPCのスパンを作成し、トレースの情報をサーバーに送るクライアントのインターセプター
+// スパンはストリームが終了したとき（RecvMsgがエラーを返したとき）か、
+// 最後まで受信せずにコンテキストがキャンセルされたときに終了する
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := Tracer().Start(ctx, strings.TrimPrefix(method, "/"),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(RPCAttributes(method)...),
+		)
+
+		cs, err := streamer(Inject(ctx), desc, cc, method, opts...)
+		if err != nil {
+			EndRPC(span, err)
+			return nil, err
+		}
+
+		ts := &tracedClientStream{ClientStream: cs, span: span, desc: desc}
+		ts.stop = context.AfterFunc(ctx, func() {
+			ts.once.Do(func() { EndRPC(span, status.FromContextError(ctx.Err()).Err()) })
+		})
+		return ts, nil
+	}
+}
+
+// 送受信したメッセージをスパンのイベントとして記録するClientStream
+type tracedClientStream struct {
+	grpc.ClientStream
+	span trace.Span
+	desc *grpc.StreamDesc
+
+	sent, received int64 // 送信と受信はそれぞれ1つのgoroutineからしか呼ばれない
+
+	once sync.Once   // スパンはコンテキストのキャンセル時にも終了するため、別のgoroutineから終了されることがある
+	stop func() bool // コンテキストのキャンセルの監視を解除する
+}
+
+func (s *tracedClientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+		MessageEvent(s.span, "SENT", s.sent, m)
+	}
+	return err
+}
+
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		// io.EOFは正常な終了
+		s.end(statusError(err))
+		return err
+	}
+
+	s.received++
+	MessageEvent(s.span, "RECEIVED", s.received, m)
+	// サーバーからのレスポンスが1つだけのRPCは、受信した時点で終了する
+	if !s.desc.ServerStreams {
+		s.end(nil)
+	}
+	return nil
+}
+
+func (s *tracedClientStream) end(err error) {
+	s.stop()
+	s.once.Do(func() { EndRPC(s.span, err) })
+}
+
+// io.EOFなどgRPCのステータスでないエラーは、正常な終了として扱う
+func statusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// スパンの出力先
+const (
+	ExporterNone   = "none"   // トレースを記録しない
+	ExporterStdout = "stdout" // 標準出力にJSONで書き出す（開発用）
+	ExporterOTLP   = "otlp"   // OTLP/gRPCでコレクターに送る
+)
+
+// このモジュールのトレーサーの名前
+const instrumentationName = "awsomeProject"
+
+// トレースの設定
+type Options struct {
+	Exporter     string  // ExporterNone、ExporterStdout、ExporterOTLPのいずれか
+	OTLPEndpoint string  // ExporterOTLPの送信先（host:port）
+	OTLPInsecure bool    // ExporterOTLPでTLSを使わない
+	SampleRatio  float64 // 親スパンのないトレースを記録する割合（0〜1）
+}
+
+// 設定に従ってスパンの出力先を作成する関数
+// ExporterNoneの場合はnilを返す
+func NewExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	switch opts.Exporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, clientOpts...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %q", opts.Exporter)
+	}
+}
+
+// スパンをexporterに書き出すTracerProviderを作成し、グローバルに設定する関数
+// exporterがnilの場合はトレースを記録しない（Tracerは何もしないスパンを返す）
+//
+// テストではtracetest.NewInMemoryExporterを渡すと、記録されたスパンを確認できる
+// 終了時には、返されたTracerProviderのShutdownを呼んで残りのスパンを書き出すこと
+func NewTracerProvider(exporter sdktrace.SpanExporter, serviceName string, sampleRatio float64) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	}
+	if exporter != nil {
+		opts = append(opts,
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+			sdktrace.WithBatcher(exporter),
+		)
+	} else {
+		opts = append(opts, sdktrace.WithSampler(sdktrace.NeverSample()))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	// W3C Trace ContextとBaggageでメタデータにトレースの情報を載せる
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return tp
+}
+
+// このモジュールのTracerを返す関数
+// グローバルのTracerProviderを使うため、NewTracerProviderを呼ぶ前は何もしないスパンを返す
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,257 @@
+package validate
+
+import (
+	"awsomeProject/money"
+	"awsomeProject/pb"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	moneypb "google.golang.org/genproto/googleapis/type/money"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// 検証ルールを満たさないフィールド
+type Violation struct {
+	Field       string // "album.tracks[0].title"のようなフィールドのパス（protoのフィールド名で表す）
+	Description string // 満たしていないルールの説明
+}
+
+// 検証ルールを満たさないフィールドがあったことを表すエラー
+// ハンドラーやインターセプターからそのまま返すと、BadRequestの詳細を付けたInvalidArgumentとしてクライアントに伝わる
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + ": " + v.Description
+	}
+
+	return "invalid " + strings.Join(parts, "; ")
+}
+
+// gRPCがエラーをステータスに変換するときに呼ばれるメソッド
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	details := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	detailed, err := st.WithDetails(details)
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// メッセージのフィールドを、proto/validate.protoのフィールドオプションで定義したルールで確認する関数
+// ルールを満たさないフィールドがある場合は、すべての違反をまとめた*Errorを返す
+// 設定されているメッセージ型のフィールドは、skipが指定されていない限り中身も再帰的に確認する
+func Message(m proto.Message) error {
+	return MessageAt("", m)
+}
+
+// Messageと同じだが、違反したフィールドのパスの先頭にpathを付ける関数
+// リクエストの一部のメッセージを、リクエスト全体を検証したときと同じパスで報告したい場合に使う
+func MessageAt(path string, m proto.Message) error {
+	v := &validator{}
+	if err := v.message(path, m.ProtoReflect()); err != nil {
+		return err
+	}
+	if len(v.violations) > 0 {
+		return &Error{Violations: v.violations}
+	}
+
+	return nil
+}
+
+// 1つのメッセージの検証中に見つかった違反を集める
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) message(path string, m protoreflect.Message) error {
+	fields := m.Descriptor().Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		if err := v.field(join(path, string(fd.Name())), m, fd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) field(path string, m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	rules, _ := proto.GetExtension(fd.Options(), pb.E_Field).(*pb.FieldRules)
+
+	switch {
+	case fd.IsMap():
+		return nil // mapのフィールドにはルールを定義していない
+	case fd.IsList():
+		list := m.Get(fd).List()
+		if rules.GetRequired() && list.Len() == 0 {
+			v.add(path, "must not be empty")
+		}
+		if r := rules.GetRepeated(); r != nil && r.MaxItems != nil && uint64(list.Len()) > r.GetMaxItems() {
+			v.add(path, "must have at most %d items", r.GetMaxItems())
+		}
+		for i := range list.Len() {
+			if err := v.value(path+"["+strconv.Itoa(i)+"]", fd, list.Get(i), rules); err != nil {
+				return err
+			}
+		}
+		return nil
+	case !m.Has(fd):
+		// ゼロ値（メッセージの場合は未設定）のフィールドはrequiredだけを確認する
+		if rules.GetRequired() {
+			if fd.Kind() == protoreflect.MessageKind {
+				v.add(path, "must be set")
+			} else {
+				v.add(path, "must not be empty")
+			}
+		}
+		return nil
+	default:
+		return v.value(path, fd, m.Get(fd), rules)
+	}
+}
+
+// 設定されている1つの値（repeatedの場合は各要素）を確認するメソッド
+func (v *validator) value(path string, fd protoreflect.FieldDescriptor, value protoreflect.Value, rules *pb.FieldRules) error {
+	switch fd.Kind() {
+	case protoreflect.StringKind:
+		return v.checkString(path, value.String(), rules.GetString_())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v.checkInt32(path, int32(value.Int()), rules.GetInt32())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch msg := value.Message().Interface().(type) {
+		case *moneypb.Money:
+			v.checkMoney(path, msg, rules.GetMoney())
+		case *durationpb.Duration:
+			v.checkDuration(path, msg, rules.GetDuration())
+		default:
+			if rules.GetSkip() {
+				return nil
+			}
+			return v.message(path, value.Message())
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) checkString(path, s string, r *pb.StringRules) error {
+	if r == nil {
+		return nil
+	}
+
+	n := uint64(utf8.RuneCountInString(s))
+	if r.MinLen != nil && n < r.GetMinLen() {
+		v.add(path, "must be at least %d characters", r.GetMinLen())
+	}
+	if r.MaxLen != nil && n > r.GetMaxLen() {
+		v.add(path, "must be at most %d characters", r.GetMaxLen())
+	}
+	if r.GetPrintable() && !printable(s) {
+		v.add(path, "must not contain control characters or leading or trailing spaces")
+	}
+	if r.GetPattern() != "" {
+		re, err := compile(r.GetPattern())
+		if err != nil {
+			return fmt.Errorf("validation rule of %s: %w", path, err)
+		}
+		if !re.MatchString(s) {
+			v.add(path, "must match pattern %q", r.GetPattern())
+		}
+	}
+
+	return nil
+}
+
+func (v *validator) checkInt32(path string, n int32, r *pb.Int32Rules) {
+	if r == nil {
+		return
+	}
+	if r.Gte != nil && n < r.GetGte() {
+		v.add(path, "must be greater than or equal to %d", r.GetGte())
+	}
+	if r.Lte != nil && n > r.GetLte() {
+		v.add(path, "must be less than or equal to %d", r.GetLte())
+	}
+}
+
+// Moneyはルールの有無にかかわらず、google/type/money.protoの仕様を満たしているか確認する
+func (v *validator) checkMoney(path string, m *moneypb.Money, r *pb.MoneyRules) {
+	if err := money.Validate(m); err != nil {
+		v.add(path, "%s", err.Error())
+		return
+	}
+	if r.GetNonNegative() && (m.Units < 0 || m.Nanos < 0) {
+		v.add(path, "must not be negative")
+	}
+}
+
+func (v *validator) checkDuration(path string, d *durationpb.Duration, r *pb.DurationRules) {
+	if err := d.CheckValid(); err != nil {
+		v.add(path, "%s", err.Error())
+		return
+	}
+	if r.GetPositive() && d.AsDuration() <= 0 {
+		v.add(path, "must be positive")
+	}
+}
+
+// 制御文字を含まず、前後に空白がないか判定する関数
+func printable(s string) bool {
+	if strings.TrimSpace(s) != s {
+		return false
+	}
+
+	return !strings.ContainsFunc(s, unicode.IsControl)
+}
+
+// ルールのpatternはリクエストごとにコンパイルせず、コンパイル済みの正規表現を使い回す
+var patterns sync.Map // map[string]*regexp.Regexp
+
+func compile(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+
+	return re, nil
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}